@@ -1,16 +1,21 @@
 package main
 
 import (
+	"flag"
 	"weather-data-aggregator-service/conf"
 	"weather-data-aggregator-service/src/server"
 )
 
 func main() {
+	// "all" also runs scheduled ingestion in this process
+	mode := flag.String("mode", string(server.ModeAPI), "api, worker or all")
+	flag.Parse()
+
 	if err := conf.Init(); err != nil {
 		panic(err)
 	}
 
-	app := server.NewApp()
+	app := server.NewApp(server.Mode(*mode))
 
 	if err := app.Run(); err != nil {
 		panic(err)
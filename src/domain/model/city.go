@@ -6,12 +6,38 @@ import (
 )
 
 type City struct {
-	ID        uuid.UUID `json:"id" bun:",pk,nullzero,type:uuid,default:uuid_generate_v4()"`
-	Name      string    `bun:"name,unique,notnull"`
+	ID uuid.UUID `json:"id" bun:",pk,nullzero,type:uuid,default:uuid_generate_v4()"`
+	// Name is unique per country, e.g. "Paris" in "FR" and "US"
+	Name      string    `bun:"name,notnull"`
 	Enabled   bool      `bun:"enabled,notnull,default:true"`
+	Latitude  *float64  `bun:"latitude"`
+	Longitude *float64  `bun:"longitude"`
+	Country   string    `bun:"country,notnull"`  // ISO 3166-1 alpha-2 code
+	Timezone  string    `bun:"timezone,notnull"` // IANA timezone
 	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:now()"`
 }
 
+// Label is name with country, e.g. "Paris, US", as name alone is ambiguous
+func (c *City) Label() string {
+	if c.Country == "" {
+		return c.Name
+	}
+	return c.Name + ", " + c.Country
+}
+
+// HasCoord reports whether city location is resolved
+func (c *City) HasCoord() bool {
+	return c.Latitude != nil && c.Longitude != nil
+}
+
+// Coord returns city location, zero if not resolved
+func (c *City) Coord() Coord {
+	if !c.HasCoord() {
+		return Coord{}
+	}
+	return Coord{Lat: *c.Latitude, Lon: *c.Longitude}
+}
+
 type СityResp struct {
 	Coord Coord  `json:"coord"`
 	Name  string `json:"name"`
@@ -21,3 +47,14 @@ type Coord struct {
 	Lat float64 `json:"lat"`
 	Lon float64 `json:"lon"`
 }
+
+type CityRequest struct {
+	Name    string `json:"name"`
+	Enabled *bool  `json:"enabled"`
+	// Country narrows geocoding, e.g. "Paris" in "FR" or "US"
+	Country string `json:"country"`
+	// Latitude, Longitude and Timezone skip geocoding when set
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	Timezone  string   `json:"timezone"`
+}
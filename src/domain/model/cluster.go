@@ -0,0 +1,8 @@
+package model
+
+type LeaderStatus struct {
+	InstanceID string `json:"instance_id"`
+	LeaderID   string `json:"leader_id"`
+	IsLeader   bool   `json:"is_leader"`
+	Token      int64  `json:"fencing_token"`
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+const (
+	FetchRunRunning   = "running"
+	FetchRunCompleted = "completed"
+	FetchRunCancelled = "cancelled"
+	// FetchRunFailed is run left running by crashed process
+	FetchRunFailed = "failed"
+
+	FetchAttemptOK        = "ok"
+	FetchAttemptFailed    = "failed"
+	FetchAttemptRejected  = "rejected" // circuit breaker is open
+	FetchAttemptCancelled = "cancelled"
+)
+
+// FetchRun is journal record of single scheduled fetch cycle
+type FetchRun struct {
+	bun.BaseModel `bun:"table:fetch_runs,alias:fr"`
+
+	ID         uuid.UUID      `json:"id" bun:",pk,nullzero,type:uuid,default:uuid_generate_v4()"`
+	Instance   string         `json:"instance" bun:"instance,notnull"`
+	Status     string         `json:"status" bun:"status,notnull"`
+	Cities     int            `json:"cities" bun:"cities,notnull"`
+	Succeeded  int            `json:"succeeded" bun:"succeeded,notnull"`
+	Partial    int            `json:"partial" bun:"partial,notnull"`
+	Failed     int            `json:"failed" bun:"failed,notnull"`
+	Skipped    int            `json:"skipped" bun:"skipped,notnull"`
+	StartedAt  time.Time      `json:"started_at" bun:"started_at,notnull,default:current_timestamp"`
+	FinishedAt *time.Time     `json:"finished_at" bun:"finished_at"`
+	Attempts   []FetchAttempt `json:"attempts,omitempty" bun:"rel:has-many,join:id=run_id"`
+}
+
+// FetchAttempt is journal record of single provider request for city
+type FetchAttempt struct {
+	bun.BaseModel `bun:"table:fetch_run_attempts,alias:fa"`
+
+	ID           uuid.UUID `json:"id" bun:",pk,nullzero,type:uuid,default:uuid_generate_v4()"`
+	RunID        uuid.UUID `json:"run_id" bun:"run_id,notnull"`
+	CityID       uuid.UUID `json:"city_id" bun:"city_id,notnull"`
+	City         *City     `json:"city,omitempty" bun:"rel:belongs-to,join:city_id=id"`
+	Provider     string    `json:"provider" bun:"provider,notnull"`
+	Status       string    `json:"status" bun:"status,notnull"`
+	LatencyMs    int64     `json:"latency_ms" bun:"latency_ms,notnull"`
+	Error        string    `json:"error,omitempty" bun:"error,notnull"`
+	CircuitState string    `json:"circuit_state" bun:"circuit_state,notnull"`
+	Retries      int       `json:"retries" bun:"retries,notnull"`
+	CreatedAt    time.Time `json:"created_at" bun:"created_at,notnull,default:current_timestamp"`
+}
+
+type FetchRunsQuery struct {
+	Limit int `query:"limit"`
+}
+
+// HealthStatus is service health with last fetch outcomes
+type HealthStatus struct {
+	Message string `json:"message"`
+	// LastRun is the latest scheduled fetch cycle
+	LastRun *FetchRun `json:"last_run,omitempty"`
+	// LastSuccess is time of last successful fetch by provider
+	LastSuccess map[string]time.Time `json:"last_success"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+const (
+	HealthUp       = "up"
+	HealthDegraded = "degraded"
+	HealthDown     = "down"
+)
+
+// HealthCheck is result of single dependency check
+type HealthCheck struct {
+	Status    string      `json:"status"`
+	Error     string      `json:"error,omitempty"`
+	LatencyMs int64       `json:"latency_ms"`
+	Details   interface{} `json:"details,omitempty"`
+}
+
+// HealthReport is overall probe result, status is the worst status of checks
+type HealthReport struct {
+	Status    string                 `json:"status"`
+	Checks    map[string]HealthCheck `json:"checks,omitempty"`
+	CheckedAt time.Time              `json:"checked_at"`
+}
+
+// CityFreshness is age of latest aggregate of city
+type CityFreshness struct {
+	LastUpdate *time.Time `json:"last_update"`
+	AgeSeconds int64      `json:"age_seconds,omitempty"`
+	Stale      bool       `json:"stale"`
+}
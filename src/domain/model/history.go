@@ -0,0 +1,84 @@
+package model
+
+import (
+	"encoding/base64"
+	"time"
+)
+
+const (
+	GranularityRaw    = "raw"
+	GranularityHourly = "hourly"
+	GranularityDaily  = "daily"
+)
+
+type HistoryQuery struct {
+	City        string `query:"city"`
+	Country     string `query:"country"`
+	From        string `query:"from"`
+	To          string `query:"to"`
+	Granularity string `query:"granularity"`
+	Source      string `query:"source"`
+	Cursor      string `query:"cursor"`
+	Limit       int    `query:"limit"`
+}
+
+// HistoryFilter is validated HistoryQuery
+type HistoryFilter struct {
+	City        string
+	Country     string
+	From        time.Time
+	To          time.Time
+	Granularity string
+	// Source selects raw provider data, empty means aggregated data
+	Source string
+	// After is start of last bucket of previous page
+	After time.Time
+	Limit int
+}
+
+type MetricStats struct {
+	Min float64 `json:"min"`
+	Avg float64 `json:"avg"`
+	Max float64 `json:"max"`
+}
+
+type HistoryBucket struct {
+	Time        time.Time   `json:"time"`
+	Samples     int         `json:"samples"`
+	Temperature MetricStats `json:"temperature"`
+	Humidity    MetricStats `json:"humidity"`
+	WindSpeed   MetricStats `json:"wind_speed"`
+}
+
+type WeatherHistory struct {
+	City        string          `json:"city"`
+	Granularity string          `json:"granularity"`
+	Source      string          `json:"source,omitempty"`
+	Buckets     []HistoryBucket `json:"buckets"`
+	NextCursor  string          `json:"next_cursor,omitempty"`
+}
+
+// SetPage keeps first limit buckets. Buckets are queried with one extra,
+// which tells that next page exists and its cursor is set.
+func (h *WeatherHistory) SetPage(buckets []HistoryBucket, limit int) {
+	h.NextCursor = ""
+	if len(buckets) > limit {
+		buckets = buckets[:limit]
+		h.NextCursor = EncodeHistoryCursor(buckets[len(buckets)-1].Time)
+	}
+	h.Buckets = buckets
+}
+
+// EncodeHistoryCursor makes opaque cursor from bucket time
+func EncodeHistoryCursor(t time.Time) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(t.Format(time.RFC3339Nano)))
+}
+
+// DecodeHistoryCursor restores bucket time from cursor
+func DecodeHistoryCursor(cursor string) (time.Time, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339Nano, string(raw))
+}
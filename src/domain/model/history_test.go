@@ -0,0 +1,58 @@
+package model
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistoryCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		time time.Time
+	}{
+		{name: "hourly bucket", time: time.Date(2024, time.March, 15, 12, 0, 0, 0, time.UTC)},
+		{name: "raw row with microseconds", time: time.Date(2024, time.March, 15, 12, 34, 56, 123456000, time.UTC)},
+		{name: "nanoseconds", time: time.Date(2024, time.March, 15, 12, 34, 56, 999999999, time.UTC)},
+		{name: "non UTC offset", time: time.Date(2024, time.March, 15, 0, 0, 0, 0, time.FixedZone("CET", 3600))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor := EncodeHistoryCursor(tt.time)
+
+			if strings.ContainsAny(cursor, "+/=") {
+				t.Errorf("cursor %q is not URL safe", cursor)
+			}
+
+			got, err := DecodeHistoryCursor(cursor)
+			if err != nil {
+				t.Fatalf("DecodeHistoryCursor() error = %v", err)
+			}
+			if !got.Equal(tt.time) {
+				t.Errorf("DecodeHistoryCursor() = %s, want %s", got, tt.time)
+			}
+		})
+	}
+}
+
+func TestDecodeHistoryCursorInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "not base64", cursor: "not a cursor!"},
+		{name: "padded base64", cursor: base64.URLEncoding.EncodeToString([]byte("2024-03-15T12:00:00Z"))},
+		{name: "not a time", cursor: base64.RawURLEncoding.EncodeToString([]byte("page-2"))},
+		{name: "date only", cursor: base64.RawURLEncoding.EncodeToString([]byte("2024-03-15"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeHistoryCursor(tt.cursor); err == nil {
+				t.Errorf("DecodeHistoryCursor(%q) error = nil, want error", tt.cursor)
+			}
+		})
+	}
+}
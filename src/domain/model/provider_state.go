@@ -0,0 +1,20 @@
+package model
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// ProviderState is data provider keeps per city between requests,
+// e.g. HTTP validators or resolved upstream grid
+type ProviderState struct {
+	bun.BaseModel `bun:"table:provider_state,alias:ps"`
+
+	Provider  string          `bun:"provider,pk"`
+	CityID    uuid.UUID       `bun:"city_id,pk,type:uuid"`
+	State     json.RawMessage `bun:"state,type:jsonb,notnull"`
+	UpdatedAt time.Time       `bun:"updated_at,notnull"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+)
+
+type ScoresQuery struct {
+	City    string `query:"city"`
+	Country string `query:"country"`
+	From    string `query:"from"`
+	To      string `query:"to"`
+}
+
+// ScoresFilter is validated ScoresQuery
+type ScoresFilter struct {
+	City    string
+	Country string
+	From    time.Time
+	To      time.Time
+}
+
+// ProviderScore is error of provider data against METAR observations
+// taken close to the same time
+type ProviderScore struct {
+	Source  string `json:"source"`
+	Samples int    `json:"samples"`
+	// TemperatureBias is mean signed error, positive when provider is too warm
+	TemperatureMAE  float64 `json:"temperature_mae"`
+	TemperatureBias float64 `json:"temperature_bias"`
+	HumidityMAE     float64 `json:"humidity_mae"`
+	WindSpeedMAE    float64 `json:"wind_speed_mae"`
+}
+
+type ProviderScores struct {
+	City        string          `json:"city"`
+	From        time.Time       `json:"from"`
+	To          time.Time       `json:"to"`
+	GroundTruth string          `json:"ground_truth"`
+	Scores      []ProviderScore `json:"scores"`
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Push protocols of personal weather stations
+const (
+	ProtocolWU      = "wu"
+	ProtocolEcowitt = "ecowitt"
+)
+
+// Station is personal weather station allowed to push readings of city.
+// Only hash of station key is stored.
+type Station struct {
+	bun.BaseModel `bun:"table:stations,alias:st"`
+
+	ID        uuid.UUID `json:"id" bun:",pk,nullzero,type:uuid,default:uuid_generate_v4()"`
+	StationID string    `json:"station_id" bun:"station_id,unique,notnull"`
+	KeyHash   string    `json:"-" bun:"key_hash,notnull"`
+	CityID    uuid.UUID `json:"city_id" bun:"city_id,type:uuid,notnull"`
+	CreatedAt time.Time `json:"created_at" bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
+
+type StationRequest struct {
+	// StationID is WU station ID or Ecowitt PASSKEY
+	StationID string    `json:"station_id"`
+	Key       string    `json:"key"`
+	CityID    uuid.UUID `json:"city_id"`
+}
+
+// StationPush is reading in imperial units as sent by station,
+// values missing in request are nil
+type StationPush struct {
+	StationID string
+	Key       string
+	Protocol  string
+	// Time is zero when station sent "now"
+	Time           time.Time
+	TempF          *float64
+	Humidity       *float64
+	DewPointF      *float64
+	WindSpeedMph   *float64
+	WindGustMph    *float64
+	WindDir        *float64
+	BaromIn        *float64
+	RainRateIn     *float64
+	DailyRainIn    *float64
+	SolarRadiation *float64
+	UV             *float64
+}
+
+// StationReading is StationPush in metric units, stored in WeatherData details
+type StationReading struct {
+	Station        string   `json:"station"`
+	Protocol       string   `json:"protocol"`
+	Temperature    float64  `json:"temperature"` // Celsius
+	Humidity       float64  `json:"humidity"`
+	WindSpeed      float64  `json:"wind_speed"`                // m/s
+	WindGust       *float64 `json:"wind_gust,omitempty"`       // m/s
+	WindDirection  *float64 `json:"wind_direction,omitempty"`  // degrees
+	DewPoint       *float64 `json:"dew_point,omitempty"`       // Celsius
+	Pressure       *float64 `json:"pressure,omitempty"`        // hPa
+	RainRate       *float64 `json:"rain_rate,omitempty"`       // mm/h
+	DailyRain      *float64 `json:"daily_rain,omitempty"`      // mm
+	SolarRadiation *float64 `json:"solar_radiation,omitempty"` // W/m2
+	UV             *float64 `json:"uv,omitempty"`
+}
@@ -1,12 +1,23 @@
 package model
 
 import (
+	"encoding/json"
 	"github.com/google/uuid"
 	"time"
 
 	"github.com/uptrace/bun"
 )
 
+// Source types of WeatherData
+const (
+	// SourceTypeProvider is model data of weather API provider
+	SourceTypeProvider = "provider"
+	// SourceTypeMETAR is airport station observation, ground truth for provider scoring
+	SourceTypeMETAR = "metar"
+	// SourceTypePWS is personal weather station reading pushed to the service
+	SourceTypePWS = "pws"
+)
+
 // WeatherData struct for normalized data
 type WeatherData struct {
 	bun.BaseModel `bun:"table:weather_data"`
@@ -15,39 +26,68 @@ type WeatherData struct {
 	CityID      uuid.UUID `json:"city_id" bun:"city_id,notnull"`
 	City        *City     `json:"city,omitempty" bun:"rel:belongs-to,join:city_id=id"`
 	Source      string    `bun:"source,notnull"`
+	SourceType  string    `bun:"source_type,notnull,nullzero,default:'provider'"`
 	Temperature float64   `bun:"temperature"`
 	Humidity    int       `bun:"humidity"`
 	WindSpeed   float64   `bun:"wind_speed"`
-	CreatedAt   time.Time `bun:"created_at,notnull,default:current_timestamp"`
+	// Details is decoded source report, e.g. METAR groups
+	Details   json.RawMessage `bun:"details,type:jsonb,nullzero"`
+	CreatedAt time.Time       `bun:"created_at,notnull,default:current_timestamp"`
 }
 
 // AggregatedWeatherData struct for aggregated data
 type AggregatedWeatherData struct {
 	bun.BaseModel `bun:"table:aggregated_weather_data"`
 
-	ID          uuid.UUID `json:"id" bun:",pk,nullzero,type:uuid,default:uuid_generate_v4()"`
-	CityID      uuid.UUID `json:"city_id" bun:"city_id,notnull"`
-	City        *City     `json:"city,omitempty" bun:"rel:belongs-to,join:city_id=id"`
-	Temperature float64   `bun:"temperature"`
-	Humidity    int       `bun:"humidity"`
-	WindSpeed   float64   `bun:"wind_speed"`
-	CreatedAt   time.Time `bun:"created_at,notnull,default:current_timestamp"`
+	ID                uuid.UUID `json:"id" bun:",pk,nullzero,type:uuid,default:uuid_generate_v4()"`
+	CityID            uuid.UUID `json:"city_id" bun:"city_id,notnull"`
+	City              *City     `json:"city,omitempty" bun:"rel:belongs-to,join:city_id=id"`
+	Temperature       float64   `bun:"temperature"`
+	Humidity          int       `bun:"humidity"`
+	WindSpeed         float64   `bun:"wind_speed"`
+	TemperatureSpread float64   `bun:"temperature_spread,notnull"`
+	HumiditySpread    float64   `bun:"humidity_spread,notnull"`
+	WindSpeedSpread   float64   `bun:"wind_speed_spread,notnull"`
+	Confidence        float64   `bun:"confidence,notnull"`
+	Sources           []string  `bun:"sources,array"`
+	MissingSources    int       `bun:"missing_sources,notnull"`
+	CreatedAt         time.Time `bun:"created_at,notnull,default:current_timestamp"`
 }
 
 type AggregatedWeatherDataResp struct {
-	City        *City   `json:"city,omitempty" bun:"rel:belongs-to,join:city_id=id"`
-	Temperature float64 `bun:"temperature"`
-	Humidity    int     `bun:"humidity"`
-	WindSpeed   float64 `bun:"wind_speed"`
+	City           *City    `json:"city,omitempty" bun:"rel:belongs-to,join:city_id=id"`
+	Temperature    float64  `bun:"temperature"`
+	Humidity       int      `bun:"humidity"`
+	WindSpeed      float64  `bun:"wind_speed"`
+	Spread         Spread   `json:"spread"`
+	Confidence     float64  `json:"confidence"`
+	Sources        []string `json:"sources"`
+	MissingSources int      `json:"missing_sources"`
+	// DistanceKm is distance from requested location to City
+	DistanceKm *float64 `json:"distance_km,omitempty"`
+	// OnDemand is set when location is not tracked and data fetched live
+	OnDemand bool `json:"on_demand,omitempty"`
+}
+
+// Spread is range of provider values used for aggregate
+type Spread struct {
+	Temperature float64 `json:"temperature"`
+	Humidity    float64 `json:"humidity"`
+	WindSpeed   float64 `json:"wind_speed"`
 }
 
 type CurrentQuery struct {
 	City string `query:"city"`
+	// Country selects one of tracked cities sharing the name
+	Country string   `query:"country"`
+	Lat     *float64 `query:"lat"`
+	Lon     *float64 `query:"lon"`
 }
 
 type ForecastQuery struct {
-	City string `query:"city"`
-	Days int    `query:"days"`
+	City    string `query:"city"`
+	Country string `query:"country"`
+	Days    int    `query:"days"`
 }
 
 type ForecastDay struct {
@@ -58,6 +98,20 @@ type ForecastDay struct {
 	Description string    `json:"description"`
 }
 
+type HourlyForecastQuery struct {
+	City    string `query:"city"`
+	Country string `query:"country"`
+	Hours   int    `query:"hours"`
+}
+
+type ForecastHour struct {
+	Time        time.Time `json:"time"`
+	Temperature float64   `json:"temperature"`
+	Humidity    int       `json:"humidity"`
+	WindSpeed   float64   `json:"wind_speed"`
+	Description string    `json:"description"`
+}
+
 type ForecastData struct {
 	City string        `json:"city"`
 	Days []ForecastDay `json:"days"`
@@ -96,6 +150,19 @@ type AggregatedForecast struct {
 	Days []AggregatedForecastDay `json:"days"`
 }
 
+type AggregatedForecastHour struct {
+	Time         time.Time `json:"time"`
+	Temperature  float64   `json:"temperature_avg"`
+	Humidity     int       `json:"humidity_avg"`
+	WindSpeed    float64   `json:"wind_speed_avg"`
+	Descriptions []string  `json:"descriptions"`
+}
+
+type AggregatedHourlyForecast struct {
+	City  string                   `json:"city"`
+	Hours []AggregatedForecastHour `json:"hours"`
+}
+
 type OneCallRespWA struct {
 	Location Location `json:"location"`
 	Forecast Forecast `json:"forecast"`
@@ -1,32 +1,113 @@
 package http
 
 import (
-	"fmt"
+	"crypto/subtle"
+	"errors"
 	sentryfiber "github.com/getsentry/sentry-go/fiber"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/gofiber/fiber/v2/middleware/compress"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
-	"os"
+	"go.opentelemetry.io/otel/trace"
+	"strconv"
 	"time"
+	"weather-data-aggregator-service/src/infrastructure/logger"
+	"weather-data-aggregator-service/src/infrastructure/metrics"
+	"weather-data-aggregator-service/src/infrastructure/tracing"
 	"weather-data-aggregator-service/src/registry"
 )
 
-func newLogger() fiber.Handler {
-	return logger.New(logger.Config{
-		Format:     "${time} ${status} ${method} ${path} (${remote_ip}) ${latency_human} ${req_header:Request-ID}\n",
-		TimeFormat: "2006/01/02 15:04:05.000", // точность до миллисекунд
-		TimeZone:   "Local",
+// newAccessLog puts request logger with request and trace IDs into
+// c.UserContext() and logs every request once it is handled
+func newAccessLog() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		ctx := logger.With(c.UserContext(), "request_id", c.GetRespHeader(fiber.HeaderXRequestID))
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			ctx = logger.With(ctx, "trace_id", sc.TraceID().String())
+		}
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		code := statusCode(c, err)
+		event := logger.Ctx(ctx).Info()
+		if code >= fiber.StatusInternalServerError {
+			event = logger.Ctx(ctx).Error()
+		}
+
+		event.
+			Str("method", c.Method()).
+			Str("route", c.Route().Path).
+			Str("path", tracing.RedactedPath(c)).
+			Int("status", code).
+			Dur("latency", time.Since(start)).
+			Str("ip", c.IP()).
+			Msg("http.request")
+
+		return err
+	}
+}
+
+// statusCode is response status, error handler has not set it yet
+func statusCode(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+
+	var e *fiber.Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return fiber.StatusInternalServerError
+}
+
+// newMetrics counts requests by route pattern, so path parameters
+// do not produce new series
+func newMetrics() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		code := statusCode(c, err)
+
+		route := c.Route().Path
+		metrics.HTTPRequests.WithLabelValues(c.Method(), route, strconv.Itoa(code)).Inc()
+		metrics.HTTPDuration.WithLabelValues(c.Method(), route).Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
+
+// adminAuth checks X-Admin-Token header, admin API is disabled
+// until admin.token is configured
+func adminAuth() fiber.Handler {
+	token := viper.GetString("admin.token")
+	if token == "" {
+		log.Warn().Msg("admin.token_not_configured")
+	}
 
-		DisableColors: false,
-		Output:        os.Stdout,
-	})
+	return func(c *fiber.Ctx) error {
+		if token == "" {
+			return fiber.NewError(fiber.StatusForbidden, "admin API is disabled, admin.token is not configured")
+		}
+		if subtle.ConstantTimeCompare([]byte(c.Get("X-Admin-Token")), []byte(token)) != 1 {
+			return fiber.NewError(fiber.StatusUnauthorized, "invalid admin token")
+		}
+		return c.Next()
+	}
 }
 
 func NewBase(f *fiber.App, c registry.APIController) {
-	env := viper.GetString("env")
+	f.Use(tracing.Middleware())
+
+	f.Use(newMetrics())
 
 	f.Use(cors.New())
 
@@ -34,53 +115,97 @@ func NewBase(f *fiber.App, c registry.APIController) {
 		Level: compress.LevelDefault,
 	}))
 
-	if env == "local" {
-		f.Use(logger.New(logger.Config{
-			Format:        "${time} ${status} ${method} ${path} (${remote_ip}) ${latency_human}\n",
-			TimeFormat:    "2006/01/02 15:04:05.000",
-			TimeZone:      "Local",
-			DisableColors: false,
-			Output:        os.Stdout,
-		}))
-
-		f.Use(func(c *fiber.Ctx) error {
-			start := time.Now()
-			err := c.Next()
-			duration := time.Since(start)
-			requestID := c.Get("Request-ID") // читаем заголовок напрямую
-
-			fmt.Fprintf(os.Stdout, "%s %d %s %s (%s) %s Request-ID: %s\n",
-				time.Now().Format("2006/01/02 15:04:05.000"),
-				c.Response().StatusCode(),
-				c.Method(),
-				c.Path(),
-				c.IP(),
-				duration,
-				requestID,
-			)
-
-			return err
-		})
-	}
+	f.Use(requestid.New())
+
+	f.Use(newAccessLog())
 
 	f.Use(recover.New())
 
 	f.Use(sentryfiber.New(sentryfiber.Options{Repanic: true}))
 }
 
+// newProbes registers Kubernetes liveness and readiness probes and Prometheus metrics
+func newProbes(f *fiber.App, c registry.APIController) {
+	probes := f.Group("/health")
+	{
+		probes.Get("/live", c.Health.Live)
+		probes.Get("/ready", c.Health.Ready)
+	}
+
+	f.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
+}
+
+// NewFiberRouter registers all routes for instance running API and worker
 func NewFiberRouter(f *fiber.App, c registry.APIController) {
 	NewBase(f, c)
+	newProbes(f, c)
+
+	apiV1 := newHealthRoute(f, c)
+	newReadRoutes(apiV1, c)
+	newWriteRoutes(f, apiV1, c)
+}
+
+// NewAPIRouter registers read-only routes for public-facing API instance
+func NewAPIRouter(f *fiber.App, c registry.APIController) {
+	NewBase(f, c)
+	newProbes(f, c)
 
-	// Base routs
+	apiV1 := newHealthRoute(f, c)
+	newReadRoutes(apiV1, c)
+}
+
+// NewWorkerRouter registers service routes, admin API and station pushes
+// for ingestion worker, they write to DB and may call keyed providers
+func NewWorkerRouter(f *fiber.App, c registry.APIController) {
+	NewBase(f, c)
+	newProbes(f, c)
+
+	apiV1 := newHealthRoute(f, c)
+	newWriteRoutes(f, apiV1, c)
+}
+
+// newHealthRoute registers base routs served in every mode
+func newHealthRoute(f *fiber.App, c registry.APIController) fiber.Router {
 	apiV1 := f.Group("/api/v1")
 	{
 		apiV1.Get("/health", c.Weather.HealthCheck)
 	}
+	return apiV1
+}
 
+// newReadRoutes registers public weather routes
+func newReadRoutes(apiV1 fiber.Router, c registry.APIController) {
 	apiV1Weather := apiV1.Group("/weather")
 	{
 		apiV1Weather.Get("/current", c.Weather.GetCurrent)
 		apiV1Weather.Get("/forecast", c.Weather.GetForecast)
+		apiV1Weather.Get("/forecast/hourly", c.Weather.GetHourlyForecast)
+		apiV1Weather.Get("/history", c.Weather.GetHistory)
+		apiV1Weather.Get("/scores", c.Weather.GetScores)
+
+	}
+}
+
+// newWriteRoutes registers station pushes and admin API
+func newWriteRoutes(f *fiber.App, apiV1 fiber.Router, c registry.APIController) {
+	// Personal weather stations push to default paths of their firmware
+	f.Get("/weatherstation/updateweatherstation.php", c.Station.PushWU)
+	f.Post("/data/report/:key", c.Station.PushEcowitt)
+
+	apiV1Admin := apiV1.Group("/admin", adminAuth())
+	{
+		apiV1Admin.Get("/cities", c.City.List)
+		apiV1Admin.Post("/cities", c.City.Create)
+		apiV1Admin.Patch("/cities/:id", c.City.Update)
+		apiV1Admin.Delete("/cities/:id", c.City.Delete)
+
+		apiV1Admin.Get("/stations", c.Station.List)
+		apiV1Admin.Post("/stations", c.Station.Create)
+		apiV1Admin.Delete("/stations/:id", c.Station.Delete)
+
+		apiV1Admin.Get("/leader", c.Cluster.GetLeader)
 
+		apiV1Admin.Get("/runs", c.FetchRun.List)
+		apiV1Admin.Get("/runs/:id", c.FetchRun.Get)
 	}
 }
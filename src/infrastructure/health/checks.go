@@ -0,0 +1,182 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+	"github.com/uptrace/bun"
+	"time"
+	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/infrastructure/weather"
+)
+
+// Checker is single dependency check used by readiness probe
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) model.HealthCheck
+}
+
+type postgresChecker struct {
+	db *bun.DB
+}
+
+// NewPostgresChecker pings Postgres, service is down without it
+func NewPostgresChecker(db *bun.DB) Checker {
+	return &postgresChecker{db}
+}
+
+func (c *postgresChecker) Name() string {
+	return "postgres"
+}
+
+func (c *postgresChecker) Check(ctx context.Context) model.HealthCheck {
+	return errorCheck(c.db.PingContext(ctx))
+}
+
+type redisChecker struct {
+	rdb *redis.Client
+}
+
+// NewRedisChecker pings Redis. Without it cache and leader election
+// do not work, but weather is still served from Postgres, so service
+// is degraded, not down.
+func NewRedisChecker(rdb *redis.Client) Checker {
+	return &redisChecker{rdb}
+}
+
+func (c *redisChecker) Name() string {
+	return "redis"
+}
+
+func (c *redisChecker) Check(ctx context.Context) model.HealthCheck {
+	if err := c.rdb.Ping(ctx).Err(); err != nil {
+		return model.HealthCheck{Status: model.HealthDegraded, Error: err.Error()}
+	}
+	return model.HealthCheck{Status: model.HealthUp}
+}
+
+type breakerChecker struct {
+	weatherClient *weather.WeatherClient
+}
+
+// NewBreakerChecker reports circuit breaker state of every provider.
+// Service is degraded when any breaker is open or no provider is enabled,
+// stored weather is still served, so restarting instance would not help.
+func NewBreakerChecker(weatherClient *weather.WeatherClient) Checker {
+	return &breakerChecker{weatherClient}
+}
+
+func (c *breakerChecker) Name() string {
+	return "providers"
+}
+
+func (c *breakerChecker) Check(context.Context) model.HealthCheck {
+	states := c.weatherClient.BreakerStates()
+
+	open := 0
+	for _, state := range states {
+		if state == weather.BreakerOpen {
+			open++
+		}
+	}
+
+	res := model.HealthCheck{Status: model.HealthUp, Details: states}
+	switch {
+	case len(states) == 0:
+		res.Status = model.HealthDegraded
+		res.Error = "no weather providers enabled"
+	case open == len(states):
+		res.Status = model.HealthDegraded
+		res.Error = "circuit breakers of all providers are open"
+	case open > 0:
+		res.Status = model.HealthDegraded
+		res.Error = fmt.Sprintf("circuit breakers of %d providers are open", open)
+	}
+
+	return res
+}
+
+type freshnessChecker struct {
+	db            *bun.DB
+	weatherClient *weather.WeatherClient
+}
+
+// NewFreshnessChecker compares latest aggregate of every tracked city with
+// health.staleness_threshold. Stale data degrades service, it is still served.
+func NewFreshnessChecker(db *bun.DB, weatherClient *weather.WeatherClient) Checker {
+	viper.SetDefault("health.staleness_threshold", 45*time.Minute)
+
+	return &freshnessChecker{db, weatherClient}
+}
+
+func (c *freshnessChecker) Name() string {
+	return "freshness"
+}
+
+func (c *freshnessChecker) Check(ctx context.Context) model.HealthCheck {
+	cities := c.weatherClient.Cities()
+	if len(cities) == 0 {
+		return model.HealthCheck{Status: model.HealthUp}
+	}
+
+	ids := make([]uuid.UUID, len(cities))
+	for i, city := range cities {
+		ids[i] = city.ID
+	}
+
+	// Latest aggregate of each tracked city is single lookup
+	// of (city_id, created_at DESC) index
+	var rows []struct {
+		CityID   string     `bun:"city_id"`
+		LastTime *time.Time `bun:"last_time"`
+	}
+	err := c.db.NewSelect().
+		TableExpr("cities AS c").
+		ColumnExpr("c.id AS city_id").
+		ColumnExpr("(SELECT max(a.created_at) FROM aggregated_weather_data AS a WHERE a.city_id = c.id) AS last_time").
+		Where("c.id IN (?)", bun.In(ids)).
+		Scan(ctx, &rows)
+	if err != nil {
+		return errorCheck(err)
+	}
+
+	last := make(map[string]time.Time, len(rows))
+	for _, r := range rows {
+		if r.LastTime != nil {
+			last[r.CityID] = *r.LastTime
+		}
+	}
+
+	threshold := viper.GetDuration("health.staleness_threshold")
+	details := make(map[string]model.CityFreshness, len(cities))
+	stale := 0
+
+	for _, city := range cities {
+		f := model.CityFreshness{Stale: true}
+		if t, ok := last[city.ID.String()]; ok {
+			age := time.Since(t)
+			f = model.CityFreshness{LastUpdate: &t, AgeSeconds: int64(age.Seconds()), Stale: age > threshold}
+		}
+		if f.Stale {
+			stale++
+		}
+		details[city.Label()] = f
+	}
+
+	res := model.HealthCheck{Status: model.HealthUp, Details: details}
+	if stale > 0 {
+		res.Status = model.HealthDegraded
+		res.Error = fmt.Sprintf("data of %d cities is older than %s", stale, threshold)
+	}
+
+	return res
+}
+
+func errorCheck(err error) model.HealthCheck {
+	if err != nil {
+		return model.HealthCheck{Status: model.HealthDown, Error: err.Error()}
+	}
+	return model.HealthCheck{Status: model.HealthUp}
+}
@@ -0,0 +1,187 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+	"github.com/uptrace/bun"
+	"os"
+	"sync"
+	"time"
+	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/infrastructure/metrics"
+)
+
+var (
+	// renewScript prolongs lease only if it is still held by this instance
+	renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+	// releaseScript drops lease only if it is still held by this instance
+	releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// Elector holds Redis lease so only one instance runs scheduled ingestion.
+// Every acquired lease gets increasing fencing token issued by Postgres,
+// so it survives loss of Redis data. Storage rejects writes with token
+// lower than already issued one.
+type Elector struct {
+	rdb           *redis.Client
+	db            *bun.DB
+	key           string
+	id            string
+	ttl           time.Duration
+	renewInterval time.Duration
+
+	mu       sync.RWMutex
+	isLeader bool
+	token    int64
+}
+
+func NewElector(rdb *redis.Client, db *bun.DB) *Elector {
+	viper.SetDefault("leader.key", "weather:leader")
+	viper.SetDefault("leader.ttl", 15*time.Second)
+	viper.SetDefault("leader.renew_interval", 5*time.Second)
+
+	host, _ := os.Hostname()
+
+	return &Elector{
+		rdb:           rdb,
+		db:            db,
+		key:           viper.GetString("leader.key"),
+		id:            fmt.Sprintf("%s-%s", host, uuid.NewString()[:8]),
+		ttl:           viper.GetDuration("leader.ttl"),
+		renewInterval: viper.GetDuration("leader.renew_interval"),
+	}
+}
+
+// ID returns this instance identifier
+func (e *Elector) ID() string {
+	return e.id
+}
+
+// IsLeader reports whether this instance holds the lease
+func (e *Elector) IsLeader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.isLeader
+}
+
+// Token returns fencing token of current lease, 0 if not leader
+func (e *Elector) Token() int64 {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if !e.isLeader {
+		return 0
+	}
+	return e.token
+}
+
+// Run acquires and renews lease until ctx is done
+func (e *Elector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.renewInterval)
+	defer ticker.Stop()
+
+	for {
+		if e.IsLeader() {
+			e.renew(ctx)
+		} else {
+			e.acquire(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Resign releases lease so other instance can take over without waiting for TTL
+func (e *Elector) Resign(ctx context.Context) error {
+	if !e.IsLeader() {
+		return nil
+	}
+
+	e.setLeader(false, 0)
+
+	return releaseScript.Run(ctx, e.rdb, []string{e.key}, e.id).Err()
+}
+
+// Status returns current leader of the cluster
+func (e *Elector) Status(ctx context.Context) (*model.LeaderStatus, error) {
+	leader, err := e.rdb.Get(ctx, e.key).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	return &model.LeaderStatus{
+		InstanceID: e.id,
+		LeaderID:   leader,
+		IsLeader:   e.IsLeader(),
+		Token:      e.Token(),
+	}, nil
+}
+
+func (e *Elector) acquire(ctx context.Context) {
+	ok, err := e.rdb.SetNX(ctx, e.key, e.id, e.ttl).Result()
+	if err != nil {
+		log.Error().Err(err).Str("instance", e.id).Msg("leader.acquire_failed")
+		return
+	}
+	if !ok {
+		return
+	}
+
+	// Issued token is stored at once, writes of previous leader fail from now on
+	var token int64
+	err = e.db.NewUpdate().Table("ingestion_fencing").
+		Set("token = token + 1").
+		Set("updated_at = NOW()").
+		Where("id = 1").
+		Returning("token").
+		Scan(ctx, &token)
+	if err != nil {
+		log.Error().Err(err).Str("instance", e.id).Msg("leader.token_failed")
+		if err := releaseScript.Run(ctx, e.rdb, []string{e.key}, e.id).Err(); err != nil {
+			log.Error().Err(err).Str("instance", e.id).Msg("leader.release_failed")
+		}
+		return
+	}
+
+	e.setLeader(true, token)
+	log.Info().Str("instance", e.id).Int64("token", token).Msg("leader.acquired")
+}
+
+func (e *Elector) renew(ctx context.Context) {
+	renewed, err := renewScript.Run(ctx, e.rdb, []string{e.key}, e.id, e.ttl.Milliseconds()).Int()
+	if err == nil && renewed == 1 {
+		return
+	}
+
+	e.setLeader(false, 0)
+	log.Error().Err(err).Str("instance", e.id).Int("renewed", renewed).Msg("leader.lost")
+}
+
+func (e *Elector) setLeader(isLeader bool, token int64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.isLeader = isLeader
+	e.token = token
+
+	metrics.SetLeader(isLeader)
+}
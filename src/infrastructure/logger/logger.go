@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+	"gopkg.in/natefinch/lumberjack.v2"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Init configures global zerolog logger from "log" config section.
+// Returned closer flushes and closes rotated log file.
+func Init() (io.Closer, error) {
+	viper.SetDefault("log.level", "info")
+	viper.SetDefault("log.format", "json")
+	viper.SetDefault("log.output", "stdout")
+	viper.SetDefault("log.file.path", "logs/service.log")
+	viper.SetDefault("log.file.max_size_mb", 100)
+	viper.SetDefault("log.file.max_backups", 5)
+	viper.SetDefault("log.file.max_age_days", 30)
+	viper.SetDefault("log.file.compress", true)
+
+	level, err := zerolog.ParseLevel(viper.GetString("log.level"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid log.level: %w", err)
+	}
+
+	var (
+		writers []io.Writer
+		file    *lumberjack.Logger
+	)
+
+	for _, output := range strings.Split(viper.GetString("log.output"), ",") {
+		switch strings.TrimSpace(output) {
+		case "stdout":
+			writers = append(writers, console(os.Stdout))
+		case "stderr":
+			writers = append(writers, console(os.Stderr))
+		case "file":
+			// File is always JSON, it is read by log shippers
+			file = &lumberjack.Logger{
+				Filename:   viper.GetString("log.file.path"),
+				MaxSize:    viper.GetInt("log.file.max_size_mb"),
+				MaxBackups: viper.GetInt("log.file.max_backups"),
+				MaxAge:     viper.GetInt("log.file.max_age_days"),
+				Compress:   viper.GetBool("log.file.compress"),
+			}
+			writers = append(writers, file)
+		default:
+			return nil, fmt.Errorf("unknown log.output %q", output)
+		}
+	}
+
+	zerolog.TimeFieldFormat = time.RFC3339Nano
+	zerolog.DurationFieldUnit = time.Millisecond
+
+	log.Logger = zerolog.New(zerolog.MultiLevelWriter(writers...)).
+		Level(level).
+		With().
+		Timestamp().
+		Str("service", viper.GetString("server_name")).
+		Logger()
+
+	// Logs of ctx without request logger go to global one
+	zerolog.DefaultContextLogger = &log.Logger
+
+	if file == nil {
+		return io.NopCloser(nil), nil
+	}
+	return file, nil
+}
+
+// console writes human readable lines when log.format is "console"
+func console(w io.Writer) io.Writer {
+	if viper.GetString("log.format") != "console" {
+		return w
+	}
+
+	return zerolog.ConsoleWriter{Out: w, TimeFormat: "2006/01/02 15:04:05.000"}
+}
+
+// Ctx returns logger of ctx with request scoped fields
+func Ctx(ctx context.Context) *zerolog.Logger {
+	return zerolog.Ctx(ctx)
+}
+
+// With returns ctx which logger has additional field
+func With(ctx context.Context, key, value string) context.Context {
+	l := zerolog.Ctx(ctx).With().Str(key, value).Logger()
+	return l.WithContext(ctx)
+}
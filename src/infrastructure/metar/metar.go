@@ -0,0 +1,241 @@
+package metar
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Observation is decoded METAR report, metric units.
+// Optional groups missing in report are nil.
+type Observation struct {
+	Station string    `json:"station"`
+	Time    time.Time `json:"time"`
+	// WindDirection in degrees, nil for variable wind
+	WindDirection *int     `json:"wind_direction,omitempty"`
+	WindSpeed     *float64 `json:"wind_speed,omitempty"` // m/s
+	WindGust      *float64 `json:"wind_gust,omitempty"`  // m/s
+	Visibility    *float64 `json:"visibility,omitempty"` // meters
+	// Weather is present weather groups, e.g. "-RA", "VCSH", "BR"
+	Weather     []string `json:"weather,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty"` // Celsius
+	DewPoint    *float64 `json:"dew_point,omitempty"`   // Celsius
+	Pressure    *float64 `json:"pressure,omitempty"`    // QNH, hPa
+	Raw         string   `json:"raw"`
+}
+
+// RelativeHumidity derives humidity from temperature and dew point (Magnus formula)
+func (o *Observation) RelativeHumidity() (float64, bool) {
+	if o.Temperature == nil || o.DewPoint == nil {
+		return 0, false
+	}
+
+	const b, c = 17.625, 243.04
+	t, td := *o.Temperature, *o.DewPoint
+	rh := 100 * math.Exp(c*b*(td-t)/((c+t)*(c+td)))
+	return math.Min(rh, 100), true
+}
+
+var (
+	stationPattern     = regexp.MustCompile(`^[A-Z][A-Z0-9]{3}$`)
+	timePattern        = regexp.MustCompile(`^(\d{2})(\d{2})(\d{2})Z$`)
+	windPattern        = regexp.MustCompile(`^(\d{3}|VRB)(\d{2,3})(?:G(\d{2,3}))?(KT|MPS|KMH)$`)
+	windVarPattern     = regexp.MustCompile(`^\d{3}V\d{3}$`)
+	visibilityPattern  = regexp.MustCompile(`^(\d{4})(?:NDV)?$`)
+	visibilitySMRegexp = regexp.MustCompile(`^([MP])?(\d+)?(?:(\d)/(\d{1,2}))?SM$`)
+	weatherPattern     = regexp.MustCompile(`^(?:\+|-|VC)?(?:MI|PR|BC|DR|BL|SH|TS|FZ)?(?:DZ|RA|SN|SG|IC|PL|GR|GS|UP|BR|FG|FU|VA|DU|SA|HZ|PY|PO|SQ|FC|SS|DS)*$`)
+	tempPattern        = regexp.MustCompile(`^(M?\d{2})/(M?\d{2}|//)?$`)
+	pressurePattern    = regexp.MustCompile(`^([QA])(\d{4})$`)
+	// remarkTempPattern is precise temperature of North American remarks, e.g. T01720083
+	remarkTempPattern = regexp.MustCompile(`^T([01])(\d{3})(?:([01])(\d{3}))?$`)
+)
+
+// Parse decodes METAR or SPECI report. Report has only day of month,
+// month and year are taken from ref (usually current time).
+// Unknown groups (clouds, runway visual range etc.) are skipped.
+func Parse(raw string, ref time.Time) (*Observation, error) {
+	fields := strings.Fields(strings.TrimSuffix(strings.TrimSpace(raw), "="))
+	if len(fields) > 0 && (fields[0] == "METAR" || fields[0] == "SPECI") {
+		fields = fields[1:]
+	}
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("report is too short")
+	}
+
+	obs := &Observation{Raw: strings.TrimSpace(raw)}
+
+	if !stationPattern.MatchString(fields[0]) {
+		return nil, fmt.Errorf("invalid station %q", fields[0])
+	}
+	obs.Station = fields[0]
+
+	t, err := parseTime(fields[1], ref)
+	if err != nil {
+		return nil, err
+	}
+	obs.Time = t
+
+	remarks := false
+	for i := 2; i < len(fields); i++ {
+		f := fields[i]
+
+		if remarks {
+			parseRemark(obs, f)
+			continue
+		}
+
+		switch {
+		case f == "RMK":
+			remarks = true
+		case f == "NOSIG" || f == "BECMG" || f == "TEMPO":
+			// Trend forecast is not an observation
+			return obs, nil
+		case f == "AUTO" || f == "COR" || windVarPattern.MatchString(f):
+		case f == "CAVOK":
+			obs.Visibility = ptr(10000.0)
+		case windPattern.MatchString(f):
+			parseWind(obs, windPattern.FindStringSubmatch(f))
+		case visibilityPattern.MatchString(f):
+			v, _ := strconv.ParseFloat(visibilityPattern.FindStringSubmatch(f)[1], 64)
+			obs.Visibility = &v
+		case strings.HasSuffix(f, "SM"):
+			// Whole and fraction parts may be separate groups, e.g. "1 1/2SM"
+			if whole, err := strconv.Atoi(fields[i-1]); err == nil && obs.Visibility == nil && strings.Contains(f, "/") {
+				f = strconv.Itoa(whole) + f
+			}
+			if v, ok := parseVisibilitySM(f); ok {
+				obs.Visibility = &v
+			}
+		case tempPattern.MatchString(f):
+			m := tempPattern.FindStringSubmatch(f)
+			obs.Temperature = parseTemp(m[1])
+			obs.DewPoint = parseTemp(m[2])
+		case pressurePattern.MatchString(f):
+			m := pressurePattern.FindStringSubmatch(f)
+			v, _ := strconv.ParseFloat(m[2], 64)
+			if m[1] == "A" {
+				// Inches of mercury with two implied decimals
+				v = math.Round(v/100*33.8639*10) / 10
+			}
+			obs.Pressure = &v
+		case f != "" && weatherPattern.MatchString(f) && f != "+" && f != "-" && f != "VC":
+			obs.Weather = append(obs.Weather, f)
+		}
+	}
+
+	return obs, nil
+}
+
+func parseTime(f string, ref time.Time) (time.Time, error) {
+	m := timePattern.FindStringSubmatch(f)
+	if m == nil {
+		return time.Time{}, fmt.Errorf("invalid time %q", f)
+	}
+
+	day, _ := strconv.Atoi(m[1])
+	hour, _ := strconv.Atoi(m[2])
+	minute, _ := strconv.Atoi(m[3])
+	if day < 1 || day > 31 || hour > 23 || minute > 59 {
+		return time.Time{}, fmt.Errorf("invalid time %q", f)
+	}
+
+	ref = ref.UTC()
+	t := time.Date(ref.Year(), ref.Month(), day, hour, minute, 0, 0, time.UTC)
+	// Day after reference day belongs to previous month
+	if day > ref.Day() {
+		t = time.Date(ref.Year(), ref.Month()-1, day, hour, minute, 0, 0, time.UTC)
+	}
+	return t, nil
+}
+
+func parseWind(obs *Observation, m []string) {
+	if m[1] != "VRB" {
+		dir, _ := strconv.Atoi(m[1])
+		obs.WindDirection = &dir
+	}
+
+	factor := 1.0
+	switch m[4] {
+	case "KT":
+		factor = 0.514444
+	case "KMH":
+		factor = 1 / 3.6
+	}
+
+	speed, _ := strconv.ParseFloat(m[2], 64)
+	obs.WindSpeed = ptr(math.Round(speed*factor*100) / 100)
+
+	if m[3] != "" {
+		gust, _ := strconv.ParseFloat(m[3], 64)
+		obs.WindGust = ptr(math.Round(gust*factor*100) / 100)
+	}
+}
+
+// parseVisibilitySM converts statute miles group like "10SM", "1/2SM",
+// "11/2SM" (joined "1 1/2SM") or "M1/4SM" to meters
+func parseVisibilitySM(f string) (float64, bool) {
+	m := visibilitySMRegexp.FindStringSubmatch(f)
+	if m == nil || (m[2] == "" && m[3] == "") {
+		return 0, false
+	}
+
+	var miles float64
+	if m[3] != "" {
+		num, _ := strconv.ParseFloat(m[3], 64)
+		den, _ := strconv.ParseFloat(m[4], 64)
+		if den == 0 {
+			return 0, false
+		}
+		miles = num / den
+	}
+	if m[2] != "" {
+		whole, _ := strconv.ParseFloat(m[2], 64)
+		miles += whole
+	}
+
+	return math.Round(miles * 1609.344), true
+}
+
+func parseTemp(s string) *float64 {
+	if s == "" {
+		return nil
+	}
+
+	v, err := strconv.ParseFloat(strings.TrimPrefix(s, "M"), 64)
+	if err != nil {
+		return nil
+	}
+	if strings.HasPrefix(s, "M") {
+		v = -v
+	}
+	return &v
+}
+
+func parseRemark(obs *Observation, f string) {
+	m := remarkTempPattern.FindStringSubmatch(f)
+	if m == nil {
+		return
+	}
+
+	obs.Temperature = remarkTemp(m[1], m[2])
+	if m[3] != "" {
+		obs.DewPoint = remarkTemp(m[3], m[4])
+	}
+}
+
+// remarkTemp decodes sign flag and tenths of degree
+func remarkTemp(sign, tenths string) *float64 {
+	v, _ := strconv.ParseFloat(tenths, 64)
+	v /= 10
+	if sign == "1" {
+		v = -v
+	}
+	return &v
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,202 @@
+package metar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	ref := time.Date(2024, time.March, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		raw         string
+		ref         time.Time
+		time        time.Time
+		visibility  *float64
+		temperature *float64
+		dewPoint    *float64
+		pressure    *float64
+	}{
+		{
+			name:        "metric report",
+			raw:         "METAR EDDB 151150Z 24012KT 9999 FEW030 08/03 Q1013 NOSIG=",
+			ref:         ref,
+			time:        time.Date(2024, time.March, 15, 11, 50, 0, 0, time.UTC),
+			visibility:  ptr(9999.0),
+			temperature: ptr(8.0),
+			dewPoint:    ptr(3.0),
+			pressure:    ptr(1013.0),
+		},
+		{
+			name:        "day after reference day is previous month",
+			raw:         "LFPG 292330Z 18005KT CAVOK 06/04 Q1020",
+			ref:         time.Date(2024, time.March, 1, 0, 10, 0, 0, time.UTC),
+			time:        time.Date(2024, time.February, 29, 23, 30, 0, 0, time.UTC),
+			visibility:  ptr(10000.0),
+			temperature: ptr(6.0),
+			dewPoint:    ptr(4.0),
+			pressure:    ptr(1020.0),
+		},
+		{
+			name:        "day after reference day in January is previous year",
+			raw:         "EGLL 312350Z 27010KT 9999 BKN012 09/07 Q1008",
+			ref:         time.Date(2025, time.January, 1, 0, 5, 0, 0, time.UTC),
+			time:        time.Date(2024, time.December, 31, 23, 50, 0, 0, time.UTC),
+			visibility:  ptr(9999.0),
+			temperature: ptr(9.0),
+			dewPoint:    ptr(7.0),
+			pressure:    ptr(1008.0),
+		},
+		{
+			name:        "whole and fraction visibility groups",
+			raw:         "KJFK 151151Z 36008KT 1 1/2SM BR OVC004 03/02 A2992",
+			ref:         ref,
+			time:        time.Date(2024, time.March, 15, 11, 51, 0, 0, time.UTC),
+			visibility:  ptr(2414.0),
+			temperature: ptr(3.0),
+			dewPoint:    ptr(2.0),
+			pressure:    ptr(1013.2),
+		},
+		{
+			name:        "visibility below quarter mile",
+			raw:         "KORD 151151Z 00000KT M1/4SM FG VV001 M01/M01 A3001",
+			ref:         ref,
+			time:        time.Date(2024, time.March, 15, 11, 51, 0, 0, time.UTC),
+			visibility:  ptr(402.0),
+			temperature: ptr(-1.0),
+			dewPoint:    ptr(-1.0),
+			pressure:    ptr(1016.3),
+		},
+		{
+			name:        "negative temperatures",
+			raw:         "ENGM 151150Z 01005KT 9999 SKC M05/M12 Q1030",
+			ref:         ref,
+			time:        time.Date(2024, time.March, 15, 11, 50, 0, 0, time.UTC),
+			visibility:  ptr(9999.0),
+			temperature: ptr(-5.0),
+			dewPoint:    ptr(-12.0),
+			pressure:    ptr(1030.0),
+		},
+		{
+			name:        "missing dew point",
+			raw:         "LEMD 151200Z 22008KT 9999 FEW040 12/// Q1018",
+			ref:         ref,
+			time:        time.Date(2024, time.March, 15, 12, 0, 0, 0, time.UTC),
+			visibility:  ptr(9999.0),
+			temperature: ptr(12.0),
+			pressure:    ptr(1018.0),
+		},
+		{
+			name:        "remarks temperature overrides rounded one",
+			raw:         "KSEA 151153Z 18010KT 10SM SCT050 17/08 A3004 RMK AO2 SLP174 T01720083",
+			ref:         ref,
+			time:        time.Date(2024, time.March, 15, 11, 53, 0, 0, time.UTC),
+			visibility:  ptr(16093.0),
+			temperature: ptr(17.2),
+			dewPoint:    ptr(8.3),
+			pressure:    ptr(1017.3),
+		},
+		{
+			name:        "negative remarks temperature",
+			raw:         "KMSP 151153Z 32012KT 10SM CLR M06/M12 A3021 RMK AO2 T10561117",
+			ref:         ref,
+			time:        time.Date(2024, time.March, 15, 11, 53, 0, 0, time.UTC),
+			visibility:  ptr(16093.0),
+			temperature: ptr(-5.6),
+			dewPoint:    ptr(-11.7),
+			pressure:    ptr(1023.0),
+		},
+		{
+			name:        "trend groups are not observation",
+			raw:         "EDDF 151150Z 25010KT 9999 BKN025 07/02 Q1012 TEMPO 4000 -RA 02/M01",
+			ref:         ref,
+			time:        time.Date(2024, time.March, 15, 11, 50, 0, 0, time.UTC),
+			visibility:  ptr(9999.0),
+			temperature: ptr(7.0),
+			dewPoint:    ptr(2.0),
+			pressure:    ptr(1012.0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obs, err := Parse(tt.raw, tt.ref)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if !obs.Time.Equal(tt.time) {
+				t.Errorf("Time = %s, want %s", obs.Time, tt.time)
+			}
+			assertFloat(t, "Visibility", obs.Visibility, tt.visibility)
+			assertFloat(t, "Temperature", obs.Temperature, tt.temperature)
+			assertFloat(t, "DewPoint", obs.DewPoint, tt.dewPoint)
+			assertFloat(t, "Pressure", obs.Pressure, tt.pressure)
+		})
+	}
+}
+
+func TestParseWind(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       string
+		direction *int
+		speed     float64
+		gust      *float64
+	}{
+		{name: "knots with gust", raw: "EDDB 151150Z 24012G25KT 9999 08/03 Q1013", direction: ptr(240), speed: 6.17, gust: ptr(12.86)},
+		{name: "meters per second", raw: "UUEE 151200Z 18004MPS 9999 M02/M05 Q1021", direction: ptr(180), speed: 4},
+		{name: "variable wind", raw: "LIRF 151150Z VRB02KT CAVOK 15/07 Q1022", speed: 1.03},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obs, err := Parse(tt.raw, time.Date(2024, time.March, 15, 12, 0, 0, 0, time.UTC))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			switch {
+			case (obs.WindDirection == nil) != (tt.direction == nil):
+				t.Errorf("WindDirection = %v, want %v", obs.WindDirection, tt.direction)
+			case obs.WindDirection != nil && *obs.WindDirection != *tt.direction:
+				t.Errorf("WindDirection = %d, want %d", *obs.WindDirection, *tt.direction)
+			}
+			assertFloat(t, "WindSpeed", obs.WindSpeed, &tt.speed)
+			assertFloat(t, "WindGust", obs.WindGust, tt.gust)
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{name: "empty", raw: ""},
+		{name: "station only", raw: "METAR EDDB"},
+		{name: "invalid station", raw: "EDDB1 151150Z 24012KT"},
+		{name: "invalid time", raw: "EDDB 152460Z 24012KT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.raw, time.Now()); err == nil {
+				t.Errorf("Parse(%q) error = nil, want error", tt.raw)
+			}
+		})
+	}
+}
+
+func assertFloat(t *testing.T, field string, got, want *float64) {
+	t.Helper()
+
+	switch {
+	case got == nil && want == nil:
+	case got == nil || want == nil:
+		t.Errorf("%s = %v, want %v", field, got, want)
+	case *got != *want:
+		t.Errorf("%s = %v, want %v", field, *got, *want)
+	}
+}
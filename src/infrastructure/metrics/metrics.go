@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sony/gobreaker"
+	"time"
+)
+
+const namespace = "weather"
+
+var (
+	ProviderRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "provider_requests_total",
+		Help:      "Provider requests by operation and status (ok, failed, rejected, cancelled).",
+	}, []string{"provider", "operation", "status"})
+
+	ProviderLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "provider_request_duration_seconds",
+		Help:      "Provider request duration including retries.",
+		Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"provider", "operation", "status"})
+
+	ProviderRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "provider_retries_total",
+		Help:      "Provider request retries after failed attempt.",
+	}, []string{"provider"})
+
+	BreakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "provider_circuit_breaker_state",
+		Help:      "Provider circuit breaker state: 0 closed, 1 half-open, 2 open.",
+	}, []string{"provider"})
+
+	RowsWritten = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rows_written_total",
+		Help:      "Rows written by ingestion by table.",
+	}, []string{"table"})
+
+	CycleDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "fetch_cycle_duration_seconds",
+		Help:      "Duration of scheduled fetch cycle.",
+		Buckets:   []float64{1, 5, 10, 30, 60, 120, 300, 600, 900},
+	})
+
+	IsLeader = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "is_leader",
+		Help:      "1 if instance holds ingestion lease, 0 otherwise.",
+	})
+
+	CycleCities = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "fetch_cycle_cities_total",
+		Help:      "Cities processed by fetch cycles by status (ok, partial, failed, skipped).",
+	}, []string{"status"})
+
+	CityLastUpdate = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "city_last_update_timestamp_seconds",
+		Help:      "Time of latest stored aggregate of city, freshness is time() minus this value.",
+	}, []string{"city", "country"})
+
+	CacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Cache lookups by cache and result (hit, miss).",
+	}, []string{"cache", "result"})
+
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "code"})
+
+	HTTPDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request duration by method and route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+)
+
+// ObserveProviderRequest counts provider request and its duration
+func ObserveProviderRequest(provider, operation, status string, duration time.Duration) {
+	ProviderRequests.WithLabelValues(provider, operation, status).Inc()
+	ProviderLatency.WithLabelValues(provider, operation, status).Observe(duration.Seconds())
+}
+
+// SetBreakerState exports circuit breaker state of provider
+func SetBreakerState(provider string, state gobreaker.State) {
+	var v float64
+	switch state {
+	case gobreaker.StateHalfOpen:
+		v = 1
+	case gobreaker.StateOpen:
+		v = 2
+	}
+	BreakerState.WithLabelValues(provider).Set(v)
+}
+
+// SetLeader exports whether instance is ingestion leader
+func SetLeader(isLeader bool) {
+	var v float64
+	if isLeader {
+		v = 1
+	}
+	IsLeader.Set(v)
+}
+
+// CacheHit counts cache lookup result
+func CacheHit(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	CacheRequests.WithLabelValues(cache, result).Inc()
+}
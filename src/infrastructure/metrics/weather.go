@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+// aggregatedSource is source label of aggregated values
+const aggregatedSource = "aggregated"
+
+var (
+	temperatureDesc = prometheus.NewDesc(namespace+"_observed_temperature_celsius",
+		"Latest stored temperature by city and source.", []string{"city", "country", "source"}, nil)
+	humidityDesc = prometheus.NewDesc(namespace+"_observed_humidity_percent",
+		"Latest stored humidity by city and source.", []string{"city", "country", "source"}, nil)
+	windSpeedDesc = prometheus.NewDesc(namespace+"_observed_wind_speed_mps",
+		"Latest stored wind speed by city and source.", []string{"city", "country", "source"}, nil)
+	observedAtDesc = prometheus.NewDesc(namespace+"_observed_timestamp_seconds",
+		"Time of latest stored values by city and source.", []string{"city", "country", "source"}, nil)
+	spreadDesc = prometheus.NewDesc(namespace+"_observed_spread",
+		"Range of provider values used for latest aggregate by city and metric.", []string{"city", "country", "metric"}, nil)
+	confidenceDesc = prometheus.NewDesc(namespace+"_observed_confidence",
+		"Confidence of latest aggregate by city.", []string{"city", "country"}, nil)
+)
+
+// weatherCollector exports latest stored weather as gauges on every scrape.
+// Values older than metrics.stale_after are not exported, so disabled
+// and dead cities disappear.
+type weatherCollector struct {
+	db         *bun.DB
+	staleAfter time.Duration
+	timeout    time.Duration
+}
+
+type latestAggregate struct {
+	City              string    `bun:"city"`
+	Country           string    `bun:"country"`
+	Temperature       float64   `bun:"temperature"`
+	Humidity          int       `bun:"humidity"`
+	WindSpeed         float64   `bun:"wind_speed"`
+	TemperatureSpread float64   `bun:"temperature_spread"`
+	HumiditySpread    float64   `bun:"humidity_spread"`
+	WindSpeedSpread   float64   `bun:"wind_speed_spread"`
+	Confidence        float64   `bun:"confidence"`
+	CreatedAt         time.Time `bun:"created_at"`
+}
+
+type latestReading struct {
+	City        string    `bun:"city"`
+	Country     string    `bun:"country"`
+	Source      string    `bun:"source"`
+	Temperature float64   `bun:"temperature"`
+	Humidity    int       `bun:"humidity"`
+	WindSpeed   float64   `bun:"wind_speed"`
+	CreatedAt   time.Time `bun:"created_at"`
+}
+
+// RegisterWeatherCollector registers collector of latest stored weather
+func RegisterWeatherCollector(db *bun.DB) {
+	viper.SetDefault("metrics.stale_after", time.Hour)
+	viper.SetDefault("metrics.collect_timeout", 5*time.Second)
+
+	prometheus.MustRegister(&weatherCollector{
+		db:         db,
+		staleAfter: viper.GetDuration("metrics.stale_after"),
+		timeout:    viper.GetDuration("metrics.collect_timeout"),
+	})
+}
+
+func (c *weatherCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- temperatureDesc
+	ch <- humidityDesc
+	ch <- windSpeedDesc
+	ch <- observedAtDesc
+	ch <- spreadDesc
+	ch <- confidenceDesc
+}
+
+func (c *weatherCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	since := time.Now().Add(-c.staleAfter)
+
+	var aggregates []latestAggregate
+	err := c.db.NewSelect().
+		TableExpr("aggregated_weather_data AS a").
+		Join("JOIN cities AS c ON c.id = a.city_id").
+		DistinctOn("a.city_id").
+		// Name is unique per country only
+		ColumnExpr("c.name AS city, c.country").
+		ColumnExpr("a.temperature, a.humidity, a.wind_speed, a.confidence, a.created_at").
+		ColumnExpr("a.temperature_spread, a.humidity_spread, a.wind_speed_spread").
+		Where("c.enabled = ?", true).
+		Where("a.created_at > ?", since).
+		OrderExpr("a.city_id, a.created_at DESC").
+		Scan(ctx, &aggregates)
+	if err != nil {
+		log.Error().Err(err).Msg("metrics.collect_aggregates_failed")
+		ch <- prometheus.NewInvalidMetric(temperatureDesc, err)
+		return
+	}
+
+	for _, a := range aggregates {
+		c.values(ch, a.City, a.Country, aggregatedSource, a.Temperature, a.Humidity, a.WindSpeed, a.CreatedAt)
+		ch <- prometheus.MustNewConstMetric(spreadDesc, prometheus.GaugeValue, a.TemperatureSpread, a.City, a.Country, "temperature")
+		ch <- prometheus.MustNewConstMetric(spreadDesc, prometheus.GaugeValue, a.HumiditySpread, a.City, a.Country, "humidity")
+		ch <- prometheus.MustNewConstMetric(spreadDesc, prometheus.GaugeValue, a.WindSpeedSpread, a.City, a.Country, "wind_speed")
+		ch <- prometheus.MustNewConstMetric(confidenceDesc, prometheus.GaugeValue, a.Confidence, a.City, a.Country)
+	}
+
+	var readings []latestReading
+	err = c.db.NewSelect().
+		TableExpr("weather_data AS w").
+		Join("JOIN cities AS c ON c.id = w.city_id").
+		DistinctOn("w.city_id, w.source").
+		ColumnExpr("c.name AS city, c.country, w.source").
+		ColumnExpr("w.temperature, w.humidity, w.wind_speed, w.created_at").
+		Where("c.enabled = ?", true).
+		Where("w.created_at > ?", since).
+		OrderExpr("w.city_id, w.source, w.created_at DESC").
+		Scan(ctx, &readings)
+	if err != nil {
+		log.Error().Err(err).Msg("metrics.collect_readings_failed")
+		ch <- prometheus.NewInvalidMetric(temperatureDesc, err)
+		return
+	}
+
+	for _, r := range readings {
+		c.values(ch, r.City, r.Country, r.Source, r.Temperature, r.Humidity, r.WindSpeed, r.CreatedAt)
+	}
+}
+
+func (c *weatherCollector) values(ch chan<- prometheus.Metric, city, country, source string, temperature float64, humidity int, windSpeed float64, at time.Time) {
+	ch <- prometheus.MustNewConstMetric(temperatureDesc, prometheus.GaugeValue, temperature, city, country, source)
+	ch <- prometheus.MustNewConstMetric(humidityDesc, prometheus.GaugeValue, float64(humidity), city, country, source)
+	ch <- prometheus.MustNewConstMetric(windSpeedDesc, prometheus.GaugeValue, windSpeed, city, country, source)
+	ch <- prometheus.MustNewConstMetric(observedAtDesc, prometheus.GaugeValue, float64(at.Unix()), city, country, source)
+}
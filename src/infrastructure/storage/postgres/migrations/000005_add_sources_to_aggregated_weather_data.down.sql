@@ -0,0 +1,3 @@
+ALTER TABLE aggregated_weather_data
+    DROP COLUMN IF EXISTS sources,
+    DROP COLUMN IF EXISTS missing_sources;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE aggregated_weather_data
+    ADD COLUMN IF NOT EXISTS sources TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS missing_sources INT NOT NULL DEFAULT 0;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE aggregated_weather_data
+    DROP COLUMN IF EXISTS temperature_spread,
+    DROP COLUMN IF EXISTS humidity_spread,
+    DROP COLUMN IF EXISTS wind_speed_spread,
+    DROP COLUMN IF EXISTS confidence;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE aggregated_weather_data
+    ADD COLUMN IF NOT EXISTS temperature_spread DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS humidity_spread DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS wind_speed_spread DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS confidence DOUBLE PRECISION NOT NULL DEFAULT 1;
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS cities_changed ON cities;
+DROP FUNCTION IF EXISTS notify_cities_changed();
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE OR REPLACE FUNCTION notify_cities_changed() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('cities_changed', TG_OP);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER cities_changed
+    AFTER INSERT OR UPDATE OR DELETE ON cities
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_cities_changed();
\ No newline at end of file
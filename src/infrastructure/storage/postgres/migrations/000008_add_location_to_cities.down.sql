@@ -0,0 +1,5 @@
+ALTER TABLE cities
+    DROP COLUMN IF EXISTS latitude,
+    DROP COLUMN IF EXISTS longitude,
+    DROP COLUMN IF EXISTS country,
+    DROP COLUMN IF EXISTS timezone;
\ No newline at end of file
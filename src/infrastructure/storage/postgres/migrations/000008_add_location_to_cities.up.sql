@@ -0,0 +1,14 @@
+ALTER TABLE cities
+    ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS country TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT '';
+
+UPDATE cities SET latitude = 50.08804, longitude = 14.42076, country = 'CZ', timezone = 'Europe/Prague'
+WHERE name = 'Prague' AND latitude IS NULL;
+
+UPDATE cities SET latitude = 51.50853, longitude = -0.12574, country = 'GB', timezone = 'Europe/London'
+WHERE name = 'London' AND latitude IS NULL;
+
+UPDATE cities SET latitude = 40.71427, longitude = -74.00597, country = 'US', timezone = 'America/New_York'
+WHERE name = 'NewYork' AND latitude IS NULL;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS ingestion_fencing;
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS ingestion_fencing (
+    id INT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    token BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO ingestion_fencing (id, token)
+VALUES (1, 0)
+ON CONFLICT (id) DO NOTHING;
\ No newline at end of file
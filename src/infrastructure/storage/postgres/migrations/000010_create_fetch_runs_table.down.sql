@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS fetch_run_attempts;
+DROP TABLE IF EXISTS fetch_runs;
\ No newline at end of file
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS fetch_runs (
+    id UUID PRIMARY KEY UNIQUE NOT NULL DEFAULT UUID_GENERATE_V4(),
+    instance TEXT NOT NULL,
+    status TEXT NOT NULL,
+    cities INT NOT NULL DEFAULT 0,
+    succeeded INT NOT NULL DEFAULT 0,
+    partial INT NOT NULL DEFAULT 0,
+    failed INT NOT NULL DEFAULT 0,
+    skipped INT NOT NULL DEFAULT 0,
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS fetch_runs_started_at_idx ON fetch_runs (started_at DESC);
+
+CREATE TABLE IF NOT EXISTS fetch_run_attempts (
+    id UUID PRIMARY KEY UNIQUE NOT NULL DEFAULT UUID_GENERATE_V4(),
+    run_id UUID NOT NULL,
+    city_id UUID NOT NULL,
+    provider TEXT NOT NULL,
+    status TEXT NOT NULL,
+    latency_ms BIGINT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    circuit_state TEXT NOT NULL DEFAULT '',
+    retries INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (run_id) REFERENCES fetch_runs(id) ON DELETE CASCADE,
+    FOREIGN KEY (city_id) REFERENCES cities(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS fetch_run_attempts_run_id_idx ON fetch_run_attempts (run_id);
+CREATE INDEX IF NOT EXISTS fetch_run_attempts_provider_status_idx ON fetch_run_attempts (provider, status, created_at DESC);
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS aggregated_weather_data_city_created_at_idx;
+DROP INDEX IF EXISTS weather_data_city_source_created_at_idx;
\ No newline at end of file
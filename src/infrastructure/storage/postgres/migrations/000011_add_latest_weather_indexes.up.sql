@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS weather_data_city_source_created_at_idx ON weather_data (city_id, source, created_at DESC);
+CREATE INDEX IF NOT EXISTS aggregated_weather_data_city_created_at_idx ON aggregated_weather_data (city_id, created_at DESC);
\ No newline at end of file
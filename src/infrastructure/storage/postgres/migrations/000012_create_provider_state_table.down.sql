@@ -0,0 +1 @@
+DROP TABLE IF EXISTS provider_state;
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS provider_state (
+    provider TEXT NOT NULL,
+    city_id UUID NOT NULL,
+    state JSONB NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (provider, city_id),
+    FOREIGN KEY (city_id) REFERENCES cities(id) ON DELETE CASCADE
+);
\ No newline at end of file
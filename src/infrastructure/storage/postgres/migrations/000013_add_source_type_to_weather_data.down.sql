@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS weather_data_city_source_type_created_at_idx;
+
+ALTER TABLE weather_data
+    DROP COLUMN IF EXISTS source_type,
+    DROP COLUMN IF EXISTS details;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE weather_data
+    ADD COLUMN IF NOT EXISTS source_type TEXT NOT NULL DEFAULT 'provider',
+    ADD COLUMN IF NOT EXISTS details JSONB;
+
+CREATE INDEX IF NOT EXISTS weather_data_city_source_type_created_at_idx ON weather_data (city_id, source_type, created_at);
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS stations (
+    id UUID PRIMARY KEY UNIQUE NOT NULL DEFAULT UUID_GENERATE_V4(),
+    station_id TEXT UNIQUE NOT NULL,
+    key_hash TEXT NOT NULL,
+    city_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (city_id) REFERENCES cities(id) ON DELETE CASCADE
+);
\ No newline at end of file
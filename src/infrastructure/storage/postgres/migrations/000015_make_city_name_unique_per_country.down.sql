@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS cities_name_country_idx;
+
+ALTER TABLE cities ADD CONSTRAINT cities_name_key UNIQUE (name);
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE cities DROP CONSTRAINT IF EXISTS cities_name_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS cities_name_country_idx ON cities (lower(name), country);
\ No newline at end of file
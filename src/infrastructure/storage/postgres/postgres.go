@@ -8,6 +8,7 @@ import (
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/pgdialect"
 	"github.com/uptrace/bun/driver/pgdriver"
+	"github.com/uptrace/bun/extra/bunotel"
 )
 
 func InitPostgres() *bun.DB {
@@ -59,6 +60,9 @@ func InitPostgres() *bun.DB {
 	//	bundebug.FromEnv("BUNDEBUG"),
 	//))
 
+	// Spans of queries, exported when tracing is enabled
+	db.AddQueryHook(bunotel.NewQueryHook(bunotel.WithDBName(dbName)))
+
 	RegisterM2M(db)
 
 	return db
@@ -0,0 +1,110 @@
+package tracing
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// redacted replaces station keys, which are sent in URL by PWS protocols
+const redacted = "REDACTED"
+
+var (
+	secretParams = map[string]bool{":key": true}
+	secretQuery  = []string{"PASSWORD"}
+)
+
+// Middleware starts server span of request and puts it into c.UserContext(),
+// handlers must pass c.UserContext() down to use cases
+func Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		carrier := propagation.HeaderCarrier{}
+		c.Request().Header.VisitAll(func(k, v []byte) {
+			carrier.Set(string(k), string(v))
+		})
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), carrier)
+
+		ctx, span := otel.Tracer(instrumentation).Start(ctx, c.Method(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Method()),
+				semconv.ClientAddress(c.IP()),
+			),
+		)
+		defer span.End()
+
+		c.SetUserContext(ctx)
+		err := c.Next()
+
+		code := c.Response().StatusCode()
+		if err != nil {
+			code = fiber.StatusInternalServerError
+			var e *fiber.Error
+			if errors.As(err, &e) {
+				code = e.Code
+			}
+		}
+
+		route := c.Route().Path
+		span.SetName(c.Method() + " " + route)
+		// Path is known to be safe once route is matched
+		span.SetAttributes(
+			semconv.HTTPRoute(route),
+			semconv.URLPath(RedactedPath(c)),
+			semconv.HTTPResponseStatusCode(code),
+		)
+		if code >= http.StatusInternalServerError {
+			if err != nil {
+				span.RecordError(err)
+			}
+			span.SetStatus(codes.Error, http.StatusText(code))
+		}
+
+		return err
+	}
+}
+
+// RedactedPath is request path with secret route parameters replaced,
+// it must be called after route is matched
+func RedactedPath(c *fiber.Ctx) string {
+	route := strings.Split(strings.TrimSuffix(c.Route().Path, "/"), "/")
+	path := strings.Split(strings.TrimSuffix(c.Path(), "/"), "/")
+	if len(route) != len(path) {
+		return c.Path()
+	}
+
+	for i, segment := range route {
+		if secretParams[segment] {
+			path[i] = redacted
+		}
+	}
+	return strings.Join(path, "/")
+}
+
+// RedactedURL is RedactedPath with query, secret query values are replaced
+func RedactedURL(c *fiber.Ctx) string {
+	path := RedactedPath(c)
+
+	raw := string(c.Request().URI().QueryString())
+	if raw == "" {
+		return path
+	}
+
+	query, err := url.ParseQuery(raw)
+	if err != nil {
+		return path
+	}
+	for _, key := range secretQuery {
+		if query.Has(key) {
+			query.Set(key, redacted)
+		}
+	}
+	return path + "?" + query.Encode()
+}
@@ -0,0 +1,276 @@
+package weather
+
+import (
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+	"math"
+	"sort"
+	"weather-data-aggregator-service/src/domain/model"
+)
+
+// Sample is single provider value of a metric
+type Sample struct {
+	Provider string
+	Value    float64
+}
+
+// Strategy reduces provider samples into single value
+type Strategy interface {
+	// Aggregate returns aggregated value and samples which were used for it
+	Aggregate(samples []Sample) (float64, []Sample)
+}
+
+type meanStrategy struct{}
+
+func (meanStrategy) Aggregate(samples []Sample) (float64, []Sample) {
+	return mean(samples), samples
+}
+
+type medianStrategy struct{}
+
+func (medianStrategy) Aggregate(samples []Sample) (float64, []Sample) {
+	return median(values(samples)), samples
+}
+
+// trimmedMeanStrategy drops trim share of lowest and highest samples
+type trimmedMeanStrategy struct {
+	trim float64
+}
+
+func (s trimmedMeanStrategy) Aggregate(samples []Sample) (float64, []Sample) {
+	sorted := sortedSamples(samples)
+
+	cut := int(math.Floor(float64(len(sorted)) * s.trim))
+	if len(sorted)-2*cut < 1 {
+		return median(values(samples)), samples
+	}
+
+	used := sorted[cut : len(sorted)-cut]
+	return mean(used), used
+}
+
+// madStrategy rejects samples further than threshold scaled MADs from median
+type madStrategy struct {
+	threshold float64
+}
+
+func (s madStrategy) Aggregate(samples []Sample) (float64, []Sample) {
+	m := median(values(samples))
+
+	deviations := make([]float64, len(samples))
+	for i, sample := range samples {
+		deviations[i] = math.Abs(sample.Value - m)
+	}
+	// 1.4826 makes MAD consistent with standard deviation for normal data
+	mad := 1.4826 * median(deviations)
+
+	// Most samples are equal, any other value would be rejected,
+	// e.g. 81 of 80/80/81. Median is robust without rejecting any.
+	if mad == 0 {
+		return m, samples
+	}
+
+	var used []Sample
+	for i, sample := range samples {
+		if deviations[i] <= s.threshold*mad {
+			used = append(used, sample)
+		}
+	}
+
+	if len(used) == 0 {
+		return m, samples
+	}
+
+	return mean(used), used
+}
+
+// weightedStrategy uses static per-provider trust weights
+type weightedStrategy struct {
+	weights       map[string]float64
+	defaultWeight float64
+}
+
+func (s weightedStrategy) Aggregate(samples []Sample) (float64, []Sample) {
+	var (
+		sum, total float64
+		used       []Sample
+	)
+
+	for _, sample := range samples {
+		weight, ok := s.weights[sample.Provider]
+		if !ok {
+			weight = s.defaultWeight
+		}
+		if weight <= 0 {
+			continue
+		}
+
+		sum += sample.Value * weight
+		total += weight
+		used = append(used, sample)
+	}
+
+	if total == 0 {
+		return mean(samples), samples
+	}
+
+	return sum / total, used
+}
+
+func newStrategy(name string, cfg *viper.Viper) (Strategy, error) {
+	switch name {
+	case "", "mean":
+		return meanStrategy{}, nil
+	case "median":
+		return medianStrategy{}, nil
+	case "trimmed_mean":
+		return trimmedMeanStrategy{trim: cfg.GetFloat64("trim")}, nil
+	case "mad":
+		return madStrategy{threshold: cfg.GetFloat64("mad_threshold")}, nil
+	case "weighted":
+		weights := make(map[string]float64)
+		for provider := range cfg.GetStringMap("weights") {
+			weights[provider] = cfg.GetFloat64("weights." + provider)
+		}
+		return weightedStrategy{
+			weights:       weights,
+			defaultWeight: cfg.GetFloat64("default_weight"),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown aggregation strategy %q", name)
+	}
+}
+
+// reading is weather data fetched by named provider
+type reading struct {
+	provider string
+	data     *model.WeatherData
+}
+
+// aggregator builds aggregated weather with configured strategy per metric
+type aggregator struct {
+	temperature Strategy
+	humidity    Strategy
+	windSpeed   Strategy
+}
+
+func newAggregator() *aggregator {
+	cfg := viper.Sub("aggregation")
+	if cfg == nil {
+		cfg = viper.New()
+	}
+	cfg.SetDefault("trim", 0.2)
+	cfg.SetDefault("mad_threshold", 3.0)
+	cfg.SetDefault("default_weight", 1.0)
+
+	strategy := func(metric string) Strategy {
+		name := cfg.GetString("strategy." + metric)
+		s, err := newStrategy(name, cfg)
+		if err != nil {
+			log.Error().Err(err).Str("metric", metric).Msg("aggregation.strategy_fallback")
+			return meanStrategy{}
+		}
+		return s
+	}
+
+	return &aggregator{
+		temperature: strategy("temperature"),
+		humidity:    strategy("humidity"),
+		windSpeed:   strategy("wind_speed"),
+	}
+}
+
+// Aggregate reduces readings of succeeded sources into single record.
+// Spread is range of used samples, confidence is share of expected
+// sources which were used by every metric.
+func (a *aggregator) Aggregate(city *model.City, readings []reading, missing int) *model.AggregatedWeatherData {
+	temperature := make([]Sample, len(readings))
+	humidity := make([]Sample, len(readings))
+	windSpeed := make([]Sample, len(readings))
+	sources := make([]string, len(readings))
+
+	for i, r := range readings {
+		temperature[i] = Sample{r.provider, r.data.Temperature}
+		humidity[i] = Sample{r.provider, float64(r.data.Humidity)}
+		windSpeed[i] = Sample{r.provider, r.data.WindSpeed}
+		sources[i] = r.data.Source
+	}
+
+	tValue, tUsed := a.temperature.Aggregate(temperature)
+	hValue, hUsed := a.humidity.Aggregate(humidity)
+	wValue, wUsed := a.windSpeed.Aggregate(windSpeed)
+
+	used := min(len(tUsed), len(hUsed), len(wUsed))
+	expected := len(readings) + missing
+
+	return &model.AggregatedWeatherData{
+		CityID:            city.ID,
+		Temperature:       tValue,
+		Humidity:          int(math.Round(hValue)),
+		WindSpeed:         math.Round(wValue*100) / 100,
+		TemperatureSpread: spread(tUsed),
+		HumiditySpread:    spread(hUsed),
+		WindSpeedSpread:   math.Round(spread(wUsed)*100) / 100,
+		Confidence:        math.Round(float64(used)/float64(expected)*100) / 100,
+		Sources:           sources,
+		MissingSources:    missing,
+	}
+}
+
+func values(samples []Sample) []float64 {
+	res := make([]float64, len(samples))
+	for i, sample := range samples {
+		res[i] = sample.Value
+	}
+	return res
+}
+
+func sortedSamples(samples []Sample) []Sample {
+	sorted := append([]Sample(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Value < sorted[j].Value
+	})
+	return sorted
+}
+
+func mean(samples []Sample) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, sample := range samples {
+		sum += sample.Value
+	}
+	return sum / float64(len(samples))
+}
+
+func median(v []float64) float64 {
+	if len(v) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), v...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// spread is difference between highest and lowest sample
+func spread(samples []Sample) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	lo, hi := samples[0].Value, samples[0].Value
+	for _, sample := range samples[1:] {
+		lo = math.Min(lo, sample.Value)
+		hi = math.Max(hi, sample.Value)
+	}
+	return hi - lo
+}
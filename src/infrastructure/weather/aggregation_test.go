@@ -0,0 +1,141 @@
+package weather
+
+import (
+	"github.com/spf13/viper"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestStrategies(t *testing.T) {
+	samples := func(values ...float64) []Sample {
+		res := make([]Sample, len(values))
+		for i, v := range values {
+			res[i] = Sample{Provider: string(rune('a' + i)), Value: v}
+		}
+		return res
+	}
+
+	tests := []struct {
+		name     string
+		strategy Strategy
+		samples  []Sample
+		value    float64
+		used     []string
+	}{
+		{
+			name:     "mean",
+			strategy: meanStrategy{},
+			samples:  samples(10, 20, 30),
+			value:    20,
+			used:     []string{"a", "b", "c"},
+		},
+		{
+			name:     "median of odd count",
+			strategy: medianStrategy{},
+			samples:  samples(10, 30, 20),
+			value:    20,
+			used:     []string{"a", "b", "c"},
+		},
+		{
+			name:     "median of even count",
+			strategy: medianStrategy{},
+			samples:  samples(40, 10, 30, 20),
+			value:    25,
+			used:     []string{"a", "b", "c", "d"},
+		},
+		{
+			name:     "trimmed mean drops lowest and highest",
+			strategy: trimmedMeanStrategy{trim: 0.2},
+			samples:  samples(100, 10, 1, 12, 11),
+			value:    11,
+			used:     []string{"b", "e", "d"},
+		},
+		{
+			name:     "trimmed mean keeps all when trim is less than one sample",
+			strategy: trimmedMeanStrategy{trim: 0.2},
+			samples:  samples(10, 20, 60),
+			value:    30,
+			used:     []string{"a", "b", "c"},
+		},
+		{
+			name:     "trimmed mean falls back to median when nothing is left",
+			strategy: trimmedMeanStrategy{trim: 0.5},
+			samples:  samples(10, 20),
+			value:    15,
+			used:     []string{"a", "b"},
+		},
+		{
+			name:     "mad rejects outlier",
+			strategy: madStrategy{threshold: 3},
+			samples:  samples(20, 21, 40, 22),
+			value:    21,
+			used:     []string{"a", "b", "d"},
+		},
+		{
+			name:     "mad keeps close samples",
+			strategy: madStrategy{threshold: 3},
+			samples:  samples(20, 21, 22),
+			value:    21,
+			used:     []string{"a", "b", "c"},
+		},
+		{
+			name:     "mad of zero is median without rejection",
+			strategy: madStrategy{threshold: 3},
+			samples:  samples(80, 80, 81),
+			value:    80,
+			used:     []string{"a", "b", "c"},
+		},
+		{
+			name:     "mad of single sample",
+			strategy: madStrategy{threshold: 3},
+			samples:  samples(15),
+			value:    15,
+			used:     []string{"a"},
+		},
+		{
+			name:     "weighted skips zero weight",
+			strategy: weightedStrategy{weights: map[string]float64{"a": 2, "b": 0}, defaultWeight: 1},
+			samples:  samples(10, 100, 25),
+			value:    15,
+			used:     []string{"a", "c"},
+		},
+		{
+			name:     "weighted falls back to mean without weights",
+			strategy: weightedStrategy{defaultWeight: 0},
+			samples:  samples(10, 20),
+			value:    15,
+			used:     []string{"a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, used := tt.strategy.Aggregate(tt.samples)
+
+			if math.Abs(value-tt.value) > 1e-9 {
+				t.Errorf("value = %v, want %v", value, tt.value)
+			}
+
+			providers := make([]string, len(used))
+			for i, sample := range used {
+				providers[i] = sample.Provider
+			}
+			if !slices.Equal(providers, tt.used) {
+				t.Errorf("used = %v, want %v", providers, tt.used)
+			}
+		})
+	}
+}
+
+func TestNewStrategy(t *testing.T) {
+	for _, name := range []string{"", "mean", "median", "trimmed_mean", "mad", "weighted"} {
+		if _, err := newStrategy(name, viper.New()); err != nil {
+			t.Errorf("newStrategy(%q) error = %v", name, err)
+		}
+	}
+
+	if _, err := newStrategy("mode", viper.New()); err == nil {
+		t.Error("newStrategy(\"mode\") error = nil, want error")
+	}
+}
@@ -2,32 +2,68 @@ package weather
 
 import (
 	"context"
-	"github.com/gofiber/fiber/v2/log"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
+	"net/http"
+	"sync"
+	"time"
 	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/infrastructure/metrics"
 )
 
 type WeatherClient struct {
-	cities            []model.City
-	dbClient          *bun.DB
-	openWeatherAPIKey string
-	weatherAPIKey     string
+	citiesMu   sync.RWMutex
+	cities     []model.City
+	dbClient   *bun.DB
+	httpClient *http.Client
+	providers  []*providerEntry
+	// metar is nil when METAR observations are disabled
+	metar *metarSource
+	// callTimeout bounds single provider call, retries included
+	callTimeout time.Duration
+	// quorum is minimal number of succeeded sources to store aggregate
+	quorum     int
+	aggregator *aggregator
+	// onAggregate hooks are called after new aggregate is stored
+	onAggregate []func(ctx context.Context, city *model.City)
+	// leadership is nil when every instance ingests
+	leadership Leadership
 }
 
-func createWeatherClient(dbClient *bun.DB) *WeatherClient {
+// Leadership tells whether instance may run scheduled ingestion
+type Leadership interface {
+	ID() string
+	IsLeader() bool
+	// Token is fencing token checked by storage before every write
+	Token() int64
+}
+
+// SetLeadership makes scheduled ingestion run only on leader instance
+func (w *WeatherClient) SetLeadership(l Leadership) {
+	w.leadership = l
+}
 
-	owKey := viper.GetString("open_weather.key")
-	waKey := viper.GetString("weather_api.key")
+func createWeatherClient(dbClient *bun.DB, keylessOnly bool) *WeatherClient {
+	viper.SetDefault("http_client.call_timeout", 30*time.Second)
 
-	if owKey == "" || waKey == "" {
-		log.Errorf("OpenWeather and WeatherAPI keys are required")
-	}
+	httpClient := newHTTPClient()
+	state := newStateStore(dbClient)
 
 	wc := &WeatherClient{
-		dbClient:          dbClient,
-		openWeatherAPIKey: owKey,
-		weatherAPIKey:     waKey,
+		dbClient:    dbClient,
+		httpClient:  httpClient,
+		providers:   loadProviders(httpClient, state, keylessOnly),
+		metar:       newMETARSource(httpClient, state),
+		callTimeout: viper.GetDuration("http_client.call_timeout"),
+		quorum:      viper.GetInt("aggregation.quorum"),
+		aggregator:  newAggregator(),
+	}
+
+	if wc.quorum < 1 {
+		wc.quorum = 1
 	}
 
 	wc.LoadCitiesFromDB()
@@ -35,25 +71,110 @@ func createWeatherClient(dbClient *bun.DB) *WeatherClient {
 }
 
 func (w *WeatherClient) LoadCitiesFromDB() {
+	if err := w.ReloadCities(context.Background()); err != nil {
+		log.Fatal().Err(err).Msg("cities.load_failed")
+	}
+}
+
+// ReloadCities replaces tracked cities with enabled cities from DB
+func (w *WeatherClient) ReloadCities(ctx context.Context) error {
 	var cities []model.City
 
 	err := w.dbClient.NewSelect().
 		Model(&cities).
 		Where("enabled = ?", true).
-		Scan(context.Background())
+		Scan(ctx)
 
 	if err != nil {
-		log.Fatalf("Failed to load cities: %v", err)
+		return err
 	}
 
+	w.citiesMu.Lock()
+	prev := w.cities
+	added, removed := diffCities(prev, cities)
 	w.cities = cities
-	log.Errorf("Loaded %d cities from DB", len(cities))
+	w.citiesMu.Unlock()
+
+	// Removed, disabled and renamed cities would keep exporting frozen time
+	labels := make(map[[2]string]bool, len(cities))
+	for _, c := range cities {
+		labels[[2]string{c.Name, c.Country}] = true
+	}
+	for _, c := range prev {
+		if !labels[[2]string{c.Name, c.Country}] {
+			metrics.CityLastUpdate.DeleteLabelValues(c.Name, c.Country)
+		}
+	}
+
+	log.Info().Int("cities", len(cities)).Strs("added", added).Strs("removed", removed).Msg("cities.loaded")
+	return nil
 }
 
-func (w *WeatherClient) GetOpenWeatherAPIKeyKey() string {
-	return w.openWeatherAPIKey
+// WatchCities reloads cities on "cities_changed" notifications of DB trigger
+// and periodically, in case notification was lost while reconnecting.
+// Blocks until ctx is done.
+func (w *WeatherClient) WatchCities(ctx context.Context) {
+	viper.SetDefault("cities.reload_interval", 5*time.Minute)
+
+	ln := pgdriver.NewListener(w.dbClient)
+	defer ln.Close()
+
+	if err := ln.Listen(ctx, "cities_changed"); err != nil {
+		log.Error().Err(err).Msg("cities.listen_failed")
+	}
+	notifications := ln.Channel()
+
+	ticker := time.NewTicker(viper.GetDuration("cities.reload_interval"))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-notifications:
+			log.Info().Str("payload", n.Payload).Msg("cities.changed")
+		case <-ticker.C:
+		}
+
+		if err := w.ReloadCities(ctx); err != nil {
+			log.Error().Err(err).Msg("cities.reload_failed")
+		}
+	}
+}
+
+// diffCities returns names of cities which appeared in and disappeared from list
+func diffCities(prev, next []model.City) (added, removed []string) {
+	prevIDs := make(map[uuid.UUID]bool, len(prev))
+	for _, c := range prev {
+		prevIDs[c.ID] = true
+	}
+
+	nextIDs := make(map[uuid.UUID]bool, len(next))
+	for _, c := range next {
+		nextIDs[c.ID] = true
+		if !prevIDs[c.ID] {
+			added = append(added, c.Name)
+		}
+	}
+
+	for _, c := range prev {
+		if !nextIDs[c.ID] {
+			removed = append(removed, c.Name)
+		}
+	}
+
+	return added, removed
+}
+
+// Cities returns snapshot of tracked cities
+func (w *WeatherClient) Cities() []model.City {
+	w.citiesMu.RLock()
+	defer w.citiesMu.RUnlock()
+
+	return append([]model.City(nil), w.cities...)
 }
 
-func (w *WeatherClient) GetWeatherAPIKey() string {
-	return w.weatherAPIKey
+// OnAggregate registers hook called after new aggregate of city is stored
+func (w *WeatherClient) OnAggregate(hook func(ctx context.Context, city *model.City)) {
+	w.onAggregate = append(w.onAggregate, hook)
 }
@@ -0,0 +1,141 @@
+package weather
+
+import (
+	"context"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/attribute"
+	"sync"
+	"time"
+	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/infrastructure/metrics"
+	"weather-data-aggregator-service/src/infrastructure/tracing"
+)
+
+type fetchStatus int
+
+const (
+	fetchOK fetchStatus = iota
+	// fetchPartial means some providers failed or quorum was not reached
+	fetchPartial
+	fetchFailed
+	fetchSkipped
+)
+
+func (s fetchStatus) String() string {
+	switch s {
+	case fetchOK:
+		return "ok"
+	case fetchPartial:
+		return "partial"
+	case fetchFailed:
+		return "failed"
+	default:
+		return "skipped"
+	}
+}
+
+// cycleSummary counts city outcomes of single fetch cycle
+type cycleSummary struct {
+	mu      sync.Mutex
+	ok      int
+	partial int
+	failed  int
+	skipped int
+}
+
+func (s *cycleSummary) add(status fetchStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch status {
+	case fetchOK:
+		s.ok++
+	case fetchPartial:
+		s.partial++
+	case fetchFailed:
+		s.failed++
+	case fetchSkipped:
+		s.skipped++
+	}
+}
+
+// fetchWeatherData fetches all tracked cities with ingestion.concurrency workers.
+// Cities not started before ingestion.cycle_timeout or ctx cancellation
+// are skipped until next cycle, started ones are cancelled.
+func (w *WeatherClient) fetchWeatherData(ctx context.Context) {
+	viper.SetDefault("ingestion.concurrency", 8)
+	viper.SetDefault("ingestion.cycle_timeout", 14*time.Minute)
+
+	if w.leadership != nil && !w.leadership.IsLeader() {
+		log.Info().Msg("ingestion.cycle_skipped_not_leader")
+		return
+	}
+
+	start := time.Now()
+	cities := w.Cities()
+	w.resolveLocations(ctx, cities)
+
+	ctx, cancel := context.WithTimeout(ctx, viper.GetDuration("ingestion.cycle_timeout"))
+	defer cancel()
+
+	ctx, span := tracing.Start(ctx, "weather.fetchCycle", attribute.Int("weather.cities", len(cities)))
+	defer span.End()
+
+	run := w.startRun(ctx, len(cities))
+
+	concurrency := viper.GetInt("ingestion.concurrency")
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		wg      sync.WaitGroup
+		summary cycleSummary
+	)
+
+	queue := make(chan *model.City)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for city := range queue {
+				summary.add(w.fetchCityWeather(ctx, run, city))
+			}
+		}()
+	}
+
+dispatch:
+	for i := range cities {
+		select {
+		case queue <- &cities[i]:
+		case <-ctx.Done():
+			for range cities[i:] {
+				summary.add(fetchSkipped)
+			}
+			log.Error().Err(ctx.Err()).Int("skipped", len(cities)-i).Msg("ingestion.cycle_stopped")
+			break dispatch
+		}
+	}
+	close(queue)
+
+	wg.Wait()
+
+	w.finishRun(ctx, run, &summary)
+
+	metrics.CycleDuration.Observe(time.Since(start).Seconds())
+	metrics.CycleCities.WithLabelValues(fetchOK.String()).Add(float64(summary.ok))
+	metrics.CycleCities.WithLabelValues(fetchPartial.String()).Add(float64(summary.partial))
+	metrics.CycleCities.WithLabelValues(fetchFailed.String()).Add(float64(summary.failed))
+	metrics.CycleCities.WithLabelValues(fetchSkipped.String()).Add(float64(summary.skipped))
+
+	log.Info().
+		Dur("duration", time.Since(start)).
+		Int("cities", len(cities)).
+		Int("ok", summary.ok).
+		Int("partial", summary.partial).
+		Int("failed", summary.failed).
+		Int("skipped", summary.skipped).
+		Msg("ingestion.cycle_finished")
+}
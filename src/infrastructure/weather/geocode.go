@@ -0,0 +1,117 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+	"math"
+	"net/url"
+	"strings"
+	"weather-data-aggregator-service/src/domain/model"
+)
+
+const defaultGeocodingURL = "https://geocoding-api.open-meteo.com/v1/search"
+
+// Geocode resolves city coordinates, country and timezone by name.
+// Country (ISO 3166-1 alpha-2) is optional and narrows the search.
+func (w *WeatherClient) Geocode(ctx context.Context, name, country string) (*model.City, error) {
+	viper.SetDefault("geocoding.base_url", defaultGeocodingURL)
+
+	params := url.Values{}
+	params.Set("name", name)
+	params.Set("count", "1")
+	params.Set("format", "json")
+	if country != "" {
+		params.Set("countryCode", strings.ToUpper(country))
+	}
+
+	var result struct {
+		Results []struct {
+			Name        string  `json:"name"`
+			Latitude    float64 `json:"latitude"`
+			Longitude   float64 `json:"longitude"`
+			CountryCode string  `json:"country_code"`
+			Timezone    string  `json:"timezone"`
+		} `json:"results"`
+	}
+
+	if err := getJSON(ctx, w.httpClient, viper.GetString("geocoding.base_url")+"?"+params.Encode(), &result); err != nil {
+		return nil, err
+	}
+
+	if len(result.Results) == 0 {
+		return nil, fmt.Errorf("city %q not found", name)
+	}
+
+	r := result.Results[0]
+	return &model.City{
+		Name:      name,
+		Latitude:  &r.Latitude,
+		Longitude: &r.Longitude,
+		Country:   r.CountryCode,
+		Timezone:  r.Timezone,
+	}, nil
+}
+
+// resolveLocations geocodes cities stored without coordinates (added before
+// locations were kept or directly in DB) and stores result. It runs in fetch
+// cycle of leader only, other instances get locations with city reload.
+func (w *WeatherClient) resolveLocations(ctx context.Context, cities []model.City) {
+	for i := range cities {
+		city := &cities[i]
+		if city.HasCoord() {
+			continue
+		}
+
+		resolved, err := w.Geocode(ctx, city.Name, city.Country)
+		if err != nil {
+			log.Error().Err(err).Str("city", city.Name).Msg("cities.geocode_failed")
+			continue
+		}
+
+		city.Latitude = resolved.Latitude
+		city.Longitude = resolved.Longitude
+		city.Country = resolved.Country
+		city.Timezone = resolved.Timezone
+
+		_, err = w.dbClient.NewUpdate().Model(city).
+			Column("latitude", "longitude", "country", "timezone").
+			WherePK().Exec(ctx)
+		if err != nil {
+			log.Error().Err(err).Str("city", city.Name).Msg("cities.location_save_failed")
+		}
+	}
+}
+
+// NearestCity returns tracked city closest to location and distance to it in km
+func (w *WeatherClient) NearestCity(coord model.Coord) (*model.City, float64, bool) {
+	var (
+		nearest  *model.City
+		distance = math.Inf(1)
+	)
+
+	for _, city := range w.Cities() {
+		if !city.HasCoord() {
+			continue
+		}
+
+		if d := DistanceKm(coord, city.Coord()); d < distance {
+			nearest, distance = &city, d
+		}
+	}
+
+	return nearest, distance, nearest != nil
+}
+
+// DistanceKm is great-circle (haversine) distance between two locations
+func DistanceKm(a, b model.Coord) float64 {
+	const earthRadiusKm = 6371.0
+
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat := (b.Lat - a.Lat) * math.Pi / 180
+	dLon := (b.Lon - a.Lon) * math.Pi / 180
+
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dLon/2), 2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
@@ -1,18 +1,27 @@
 package weather
 
 import (
+	"context"
 	"fmt"
 	crn "github.com/robfig/cron/v3"
 	"github.com/uptrace/bun"
 )
 
-func InitWeatherAPI(db *bun.DB) *WeatherClient {
-	return createWeatherClient(db)
+// InitWeatherAPI creates weather client, keylessOnly leaves out providers
+// needing API key, so keys are not required by public-facing instances
+func InitWeatherAPI(db *bun.DB, keylessOnly bool) *WeatherClient {
+	return createWeatherClient(db, keylessOnly)
 }
 
-func InitWeatherCronJobs(cronJobRunner *crn.Cron, c *WeatherClient) error {
+// InitWeatherCronJobs schedules fetch cycles and daily journal pruning,
+// ctx cancels running cycle on shutdown
+func InitWeatherCronJobs(ctx context.Context, cronJobRunner *crn.Cron, c *WeatherClient) error {
 
-	if _, err := cronJobRunner.AddFunc("*/15 * * * *", c.fetchWeatherData); err != nil {
+	if _, err := cronJobRunner.AddFunc("*/15 * * * *", func() { c.fetchWeatherData(ctx) }); err != nil {
+		return fmt.Errorf("InitWeatherCronJobs: %s", err)
+	}
+
+	if _, err := cronJobRunner.AddFunc("30 3 * * *", func() { c.pruneRuns(ctx) }); err != nil {
 		return fmt.Errorf("InitWeatherCronJobs: %s", err)
 	}
 
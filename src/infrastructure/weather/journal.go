@@ -0,0 +1,144 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"github.com/rs/zerolog/log"
+	"github.com/sony/gobreaker"
+	"github.com/spf13/viper"
+	"os"
+	"time"
+	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/infrastructure/logger"
+)
+
+// startRun journals beginning of fetch cycle, nil run disables journaling of the cycle
+func (w *WeatherClient) startRun(ctx context.Context, cities int) *model.FetchRun {
+	w.failStaleRuns(ctx)
+
+	run := &model.FetchRun{
+		Instance:  w.instanceID(),
+		Status:    model.FetchRunRunning,
+		Cities:    cities,
+		StartedAt: time.Now(),
+	}
+
+	if _, err := w.dbClient.NewInsert().Model(run).Returning("id").Exec(ctx); err != nil {
+		log.Error().Err(err).Msg("journal.run_start_failed")
+		return nil
+	}
+
+	return run
+}
+
+// failStaleRuns marks runs still running after ingestion.cycle_timeout as failed,
+// their process crashed or was killed before finishing them
+func (w *WeatherClient) failStaleRuns(ctx context.Context) {
+	res, err := w.dbClient.NewUpdate().Model((*model.FetchRun)(nil)).
+		Set("status = ?", model.FetchRunFailed).
+		Set("finished_at = NOW()").
+		Where("status = ?", model.FetchRunRunning).
+		Where("started_at < ?", time.Now().Add(-viper.GetDuration("ingestion.cycle_timeout"))).
+		Exec(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("journal.fail_stale_runs_failed")
+		return
+	}
+
+	if n, _ := res.RowsAffected(); n > 0 {
+		log.Warn().Int64("runs", n).Msg("journal.stale_runs_failed")
+	}
+}
+
+// finishRun stores cycle summary, it is written even after shutdown cancelled ctx
+func (w *WeatherClient) finishRun(ctx context.Context, run *model.FetchRun, summary *cycleSummary) {
+	if run == nil {
+		return
+	}
+
+	run.Status = model.FetchRunCompleted
+	if ctx.Err() != nil {
+		run.Status = model.FetchRunCancelled
+	}
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Succeeded = summary.ok
+	run.Partial = summary.partial
+	run.Failed = summary.failed
+	run.Skipped = summary.skipped
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	_, err := w.dbClient.NewUpdate().Model(run).
+		Column("status", "finished_at", "succeeded", "partial", "failed", "skipped").
+		WherePK().Exec(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("run_id", run.ID.String()).Msg("journal.run_finish_failed")
+	}
+}
+
+// recordAttempts journals provider requests made for city during run
+func (w *WeatherClient) recordAttempts(ctx context.Context, run *model.FetchRun, city *model.City, attempts []model.FetchAttempt) {
+	if run == nil || len(attempts) == 0 {
+		return
+	}
+
+	for i := range attempts {
+		attempts[i].RunID = run.ID
+		attempts[i].CityID = city.ID
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if _, err := w.dbClient.NewInsert().Model(&attempts).Exec(ctx); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("journal.attempts_failed")
+	}
+}
+
+// pruneRuns deletes fetch runs older than journal.retention, their attempts
+// are deleted by cascade. Only leader prunes, as only leader writes journal.
+func (w *WeatherClient) pruneRuns(ctx context.Context) {
+	viper.SetDefault("journal.retention", 30*24*time.Hour)
+
+	if w.leadership != nil && !w.leadership.IsLeader() {
+		return
+	}
+
+	res, err := w.dbClient.NewDelete().Model((*model.FetchRun)(nil)).
+		Where("started_at < ?", time.Now().Add(-viper.GetDuration("journal.retention"))).
+		Exec(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("journal.prune_failed")
+		return
+	}
+
+	n, _ := res.RowsAffected()
+	log.Info().Int64("runs", n).Msg("journal.runs_pruned")
+}
+
+// attemptStatus classifies provider call error for journal
+func attemptStatus(ctx context.Context, err error) string {
+	switch {
+	case err == nil:
+		return model.FetchAttemptOK
+	case errors.Is(err, gobreaker.ErrOpenState), errors.Is(err, gobreaker.ErrTooManyRequests):
+		return model.FetchAttemptRejected
+	case errors.Is(ctx.Err(), context.Canceled):
+		// Exceeded http_client.call_timeout is failure of provider
+		return model.FetchAttemptCancelled
+	default:
+		return model.FetchAttemptFailed
+	}
+}
+
+func (w *WeatherClient) instanceID() string {
+	if w.leadership != nil {
+		return w.leadership.ID()
+	}
+
+	host, _ := os.Hostname()
+	return host
+}
@@ -0,0 +1,244 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/attribute"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/infrastructure/logger"
+	"weather-data-aggregator-service/src/infrastructure/metar"
+	"weather-data-aggregator-service/src/infrastructure/metrics"
+	"weather-data-aggregator-service/src/infrastructure/tracing"
+)
+
+const defaultMETARURL = "https://aviationweather.gov/api/data"
+
+// metarSource fetches raw observations of airport station nearest to city
+// from aviationweather.gov. Observations are stored next to provider data
+// but are not aggregated, they are ground truth for provider scoring.
+type metarSource struct {
+	client  *http.Client
+	state   StateStore
+	baseURL string
+	// searchRadiusKm limits distance from city to station
+	searchRadiusKm float64
+	// maxAge drops reports of stations which stopped reporting
+	maxAge time.Duration
+	// stations are configured ICAO codes by lower case city name
+	stations map[string]string
+}
+
+// metarState is station resolved for city
+type metarState struct {
+	Station    string  `json:"station"`
+	DistanceKm float64 `json:"distance_km"`
+}
+
+// newMETARSource returns nil when metar.enabled is false
+func newMETARSource(client *http.Client, state StateStore) *metarSource {
+	cfg := viper.Sub("metar")
+	if cfg == nil {
+		cfg = viper.New()
+	}
+	cfg.SetDefault("enabled", false)
+	cfg.SetDefault("base_url", defaultMETARURL)
+	cfg.SetDefault("search_radius_km", 50)
+	cfg.SetDefault("max_age", 2*time.Hour)
+
+	if !cfg.GetBool("enabled") {
+		return nil
+	}
+
+	stations := make(map[string]string)
+	for city, station := range cfg.GetStringMapString("stations") {
+		stations[strings.ToLower(city)] = strings.ToUpper(station)
+	}
+
+	return &metarSource{
+		client:         client,
+		state:          state,
+		baseURL:        strings.TrimSuffix(cfg.GetString("base_url"), "/"),
+		searchRadiusKm: cfg.GetFloat64("search_radius_km"),
+		maxAge:         cfg.GetDuration("max_age"),
+		stations:       stations,
+	}
+}
+
+// Fetch returns latest observation of city station, CreatedAt is observation time
+func (s *metarSource) Fetch(ctx context.Context, city *model.City, timeNow time.Time) (*model.WeatherData, error) {
+	station, err := s.station(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("ids", station)
+	params.Set("format", "json")
+
+	var reports []struct {
+		RawOb string `json:"rawOb"`
+	}
+
+	// No recent report is answered with empty body
+	err = getJSON(ctx, s.client, s.baseURL+"/metar?"+params.Encode(), &reports)
+	if errors.Is(err, io.EOF) || (err == nil && len(reports) == 0) {
+		return nil, fmt.Errorf("no recent report of %s", station)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	obs, err := metar.Parse(reports[0].RawOb, timeNow)
+	if err != nil {
+		return nil, fmt.Errorf("parse report of %s: %w", station, err)
+	}
+
+	if timeNow.Sub(obs.Time) > s.maxAge {
+		return nil, fmt.Errorf("report of %s is older than %s", station, s.maxAge)
+	}
+
+	humidity, ok := obs.RelativeHumidity()
+	if !ok || obs.WindSpeed == nil {
+		return nil, fmt.Errorf("report of %s has no temperature, dew point or wind", station)
+	}
+
+	details, err := json.Marshal(obs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.WeatherData{
+		CityID:      city.ID,
+		Source:      "METAR",
+		SourceType:  model.SourceTypeMETAR,
+		Temperature: *obs.Temperature,
+		Humidity:    int(math.Round(humidity)),
+		WindSpeed:   *obs.WindSpeed,
+		Details:     details,
+		CreatedAt:   obs.Time,
+	}, nil
+}
+
+// station returns configured or stored station of city, otherwise
+// nearest station reporting METAR within search radius
+func (s *metarSource) station(ctx context.Context, city *model.City) (string, error) {
+	if station, ok := s.stations[strings.ToLower(city.Name)]; ok {
+		return station, nil
+	}
+
+	var state metarState
+	found, err := s.state.Load(ctx, "metar", city.ID, &state)
+	if err != nil {
+		logger.Ctx(ctx).Warn().Err(err).Str("provider", "metar").Msg("provider.state_load_failed")
+	}
+	if found && err == nil && state.Station != "" {
+		return state.Station, nil
+	}
+
+	coord := city.Coord()
+	dLat := s.searchRadiusKm / 111
+	dLon := s.searchRadiusKm / (111 * math.Max(math.Cos(coord.Lat*math.Pi/180), 0.01))
+
+	params := url.Values{}
+	params.Set("bbox", strings.Join([]string{
+		strconv.FormatFloat(coord.Lat-dLat, 'f', 4, 64),
+		strconv.FormatFloat(coord.Lon-dLon, 'f', 4, 64),
+		strconv.FormatFloat(coord.Lat+dLat, 'f', 4, 64),
+		strconv.FormatFloat(coord.Lon+dLon, 'f', 4, 64),
+	}, ","))
+	params.Set("format", "json")
+
+	var stations []struct {
+		ICAO     string   `json:"icaoId"`
+		Lat      float64  `json:"lat"`
+		Lon      float64  `json:"lon"`
+		SiteType []string `json:"siteType"`
+	}
+
+	err = getJSON(ctx, s.client, s.baseURL+"/stationinfo?"+params.Encode(), &stations)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("search stations: %w", err)
+	}
+
+	state = metarState{DistanceKm: math.Inf(1)}
+	for _, st := range stations {
+		if len(st.ICAO) != 4 || !slices.Contains(st.SiteType, "METAR") {
+			continue
+		}
+		if d := DistanceKm(coord, model.Coord{Lat: st.Lat, Lon: st.Lon}); d <= s.searchRadiusKm && d < state.DistanceKm {
+			state = metarState{Station: st.ICAO, DistanceKm: math.Round(d*10) / 10}
+		}
+	}
+	if state.Station == "" {
+		return "", fmt.Errorf("no METAR station within %.0f km", s.searchRadiusKm)
+	}
+
+	if err := s.state.Save(ctx, "metar", city.ID, &state); err != nil {
+		logger.Ctx(ctx).Warn().Err(err).Str("provider", "metar").Msg("provider.state_save_failed")
+	}
+
+	logger.Ctx(ctx).Info().
+		Str("station", state.Station).
+		Float64("distance_km", state.DistanceKm).
+		Msg("metar.station_resolved")
+
+	return state.Station, nil
+}
+
+// fetchObservation requests METAR of city station, nil data means source
+// is disabled, failed or station has not issued new report since last cycle
+func (w *WeatherClient) fetchObservation(ctx context.Context, city *model.City, timeNow time.Time) (*model.WeatherData, *model.FetchAttempt) {
+	if w.metar == nil {
+		return nil, nil
+	}
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, w.callTimeout)
+	defer cancel()
+
+	ctx, span := tracing.Start(ctx, "provider.observation",
+		attribute.String("weather.provider", "metar"),
+		attribute.String("weather.city", city.Name),
+	)
+
+	data, err := w.metar.Fetch(ctx, city, timeNow)
+	if err == nil {
+		var stored bool
+		stored, err = w.dbClient.NewSelect().Model((*model.WeatherData)(nil)).
+			Where("city_id = ?", city.ID).
+			Where("source_type = ?", model.SourceTypeMETAR).
+			Where("created_at = ?", data.CreatedAt).
+			Exists(ctx)
+		if stored {
+			data = nil
+		}
+	}
+
+	attempt := &model.FetchAttempt{
+		Provider:  "metar",
+		Status:    attemptStatus(ctx, err),
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+
+	metrics.ObserveProviderRequest("metar", "observation", attempt.Status, time.Since(start))
+	tracing.End(span, err)
+
+	if err != nil {
+		attempt.Error = err.Error()
+		logger.Ctx(ctx).Error().Err(err).Str("provider", "metar").Msg("provider.observation_failed")
+		return nil, attempt
+	}
+
+	return data, attempt
+}
@@ -0,0 +1,392 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/cenk/backoff"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/infrastructure/logger"
+)
+
+const defaultMetNoURL = "https://api.met.no/weatherapi/locationforecast/2.0/compact"
+
+// metNoUntrackedLimit bounds documents of untracked locations kept in memory
+const metNoUntrackedLimit = 256
+
+func init() {
+	RegisterProvider("metno", false, newMetNoProvider, Keyless)
+}
+
+// metNoProvider uses MET Norway Locationforecast. Terms of service require
+// identifying User-Agent and reusing response until its Expires header,
+// so document and its validators are kept in StateStore per city.
+// Untracked locations have no stored state, their documents are kept
+// in memory by request coordinates.
+type metNoProvider struct {
+	client    *http.Client
+	state     StateStore
+	baseURL   string
+	userAgent string
+
+	untrackedMu sync.Mutex
+	untracked   map[string]metNoState
+}
+
+// metNoState is stored Locationforecast document with its HTTP validators
+type metNoState struct {
+	Expires      time.Time       `json:"expires"`
+	LastModified string          `json:"last_modified,omitempty"`
+	Body         json.RawMessage `json:"body"`
+}
+
+type metNoForecast struct {
+	Properties struct {
+		Timeseries []metNoStep `json:"timeseries"`
+	} `json:"properties"`
+}
+
+type metNoStep struct {
+	Time time.Time `json:"time"`
+	Data struct {
+		Instant struct {
+			Details struct {
+				AirTemperature   float64 `json:"air_temperature"`
+				RelativeHumidity float64 `json:"relative_humidity"`
+				WindSpeed        float64 `json:"wind_speed"`
+			} `json:"details"`
+		} `json:"instant"`
+		Next1Hours *metNoPeriod `json:"next_1_hours"`
+		Next6Hours *metNoPeriod `json:"next_6_hours"`
+	} `json:"data"`
+}
+
+type metNoPeriod struct {
+	Summary struct {
+		SymbolCode string `json:"symbol_code"`
+	} `json:"summary"`
+}
+
+func newMetNoProvider(cfg *viper.Viper, client *http.Client, state StateStore) (Provider, error) {
+	cfg.SetDefault("base_url", defaultMetNoURL)
+
+	userAgent := cfg.GetString("user_agent")
+	if userAgent == "" {
+		return nil, fmt.Errorf("user_agent is required by MET Norway terms of service")
+	}
+
+	return &metNoProvider{
+		client:    client,
+		state:     state,
+		baseURL:   cfg.GetString("base_url"),
+		userAgent: userAgent,
+		untracked: make(map[string]metNoState),
+	}, nil
+}
+
+func (p *metNoProvider) Name() string {
+	return "metno"
+}
+
+func (p *metNoProvider) Capabilities() Capabilities {
+	return Capabilities{
+		Current:          true,
+		Forecast:         true,
+		MaxForecastDays:  9,
+		MaxForecastHours: 48,
+	}
+}
+
+func (p *metNoProvider) FetchCurrent(ctx context.Context, city *model.City, timeNow time.Time) (*model.WeatherData, error) {
+	forecast, err := p.forecast(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+
+	// Latest step not in the future, document is updated hourly.
+	// Step time is kept, so reused document does not look fresh.
+	var step *metNoStep
+	for i := range forecast.Properties.Timeseries {
+		s := &forecast.Properties.Timeseries[i]
+		if s.Time.After(timeNow) {
+			break
+		}
+		step = s
+	}
+	if step == nil {
+		return nil, fmt.Errorf("no forecast step before %s", timeNow.UTC().Format(time.RFC3339))
+	}
+
+	details := step.Data.Instant.Details
+	return &model.WeatherData{
+		CityID:      city.ID,
+		Source:      "MET Norway",
+		Temperature: details.AirTemperature,
+		Humidity:    int(math.Round(details.RelativeHumidity)),
+		WindSpeed:   details.WindSpeed,
+		CreatedAt:   step.Time,
+	}, nil
+}
+
+func (p *metNoProvider) FetchForecast(ctx context.Context, city *model.City, days int) ([]model.ForecastDay, error) {
+	if days < 1 || days > 9 {
+		return nil, fmt.Errorf("days must be between 1 and 9")
+	}
+
+	forecast, err := p.forecast(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+
+	type daySum struct {
+		temperature, humidity, windSpeed float64
+		n                                int
+		description                      string
+	}
+
+	sums := make(map[time.Time]*daySum)
+	var dates []time.Time
+	for _, s := range forecast.Properties.Timeseries {
+		date := s.Time.UTC().Truncate(24 * time.Hour)
+
+		sum, ok := sums[date]
+		if !ok {
+			sum = &daySum{}
+			sums[date] = sum
+			dates = append(dates, date)
+		}
+
+		details := s.Data.Instant.Details
+		sum.temperature += details.AirTemperature
+		sum.humidity += details.RelativeHumidity
+		sum.windSpeed += details.WindSpeed
+		sum.n++
+
+		// Noon symbol describes the day best, the first one is fallback
+		if sum.description == "" || s.Time.UTC().Hour() == 12 {
+			if d := metNoDescription(s); d != "" {
+				sum.description = d
+			}
+		}
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	if len(dates) > days {
+		dates = dates[:days]
+	}
+
+	result := make([]model.ForecastDay, len(dates))
+	for i, date := range dates {
+		sum := sums[date]
+		n := float64(sum.n)
+		result[i] = model.ForecastDay{
+			Date:        date,
+			Temperature: math.Round(sum.temperature/n*100) / 100,
+			Humidity:    int(math.Round(sum.humidity / n)),
+			WindSpeed:   math.Round(sum.windSpeed/n*100) / 100,
+			Description: sum.description,
+		}
+	}
+
+	return result, nil
+}
+
+func (p *metNoProvider) FetchHourly(ctx context.Context, city *model.City, hours int) ([]model.ForecastHour, error) {
+	if hours < 1 || hours > 48 {
+		return nil, fmt.Errorf("hours must be between 1 and 48")
+	}
+
+	forecast, err := p.forecast(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+
+	// Steps are hourly for about two days, then 6-hourly
+	now := time.Now().Truncate(time.Hour)
+	result := make([]model.ForecastHour, 0, hours)
+	for _, s := range forecast.Properties.Timeseries {
+		if s.Time.Before(now) {
+			continue
+		}
+		if len(result) == hours || s.Data.Next1Hours == nil {
+			break
+		}
+
+		details := s.Data.Instant.Details
+		result = append(result, model.ForecastHour{
+			Time:        s.Time.UTC(),
+			Temperature: details.AirTemperature,
+			Humidity:    int(math.Round(details.RelativeHumidity)),
+			WindSpeed:   details.WindSpeed,
+			Description: metNoDescription(s),
+		})
+	}
+
+	return result, nil
+}
+
+// forecast returns stored document until it expires, then revalidates it
+// with If-Modified-Since
+func (p *metNoProvider) forecast(ctx context.Context, city *model.City) (*metNoForecast, error) {
+	coord := city.Coord()
+
+	// More than 4 decimals are rejected by the API
+	lat := strconv.FormatFloat(coord.Lat, 'f', 4, 64)
+	lon := strconv.FormatFloat(coord.Lon, 'f', 4, 64)
+
+	var state metNoState
+	found, err := p.load(ctx, city.ID, lat+","+lon, &state)
+	if err != nil {
+		logger.Ctx(ctx).Warn().Err(err).Str("provider", p.Name()).Msg("provider.state_load_failed")
+		found, state = false, metNoState{}
+	}
+
+	if !found || time.Now().After(state.Expires) || len(state.Body) == 0 {
+		if err := p.refresh(ctx, lat, lon, &state); err != nil {
+			return nil, err
+		}
+
+		if err := p.save(ctx, city.ID, lat+","+lon, &state); err != nil {
+			logger.Ctx(ctx).Warn().Err(err).Str("provider", p.Name()).Msg("provider.state_save_failed")
+		}
+	}
+
+	var forecast metNoForecast
+	if err := json.Unmarshal(state.Body, &forecast); err != nil {
+		return nil, fmt.Errorf("decode forecast: %w", err)
+	}
+
+	return &forecast, nil
+}
+
+// load reads state of tracked city from StateStore, state of untracked
+// location (nil city ID) from memory by its coordinates
+func (p *metNoProvider) load(ctx context.Context, cityID uuid.UUID, coord string, state *metNoState) (bool, error) {
+	if cityID != uuid.Nil {
+		return p.state.Load(ctx, p.Name(), cityID, state)
+	}
+
+	p.untrackedMu.Lock()
+	defer p.untrackedMu.Unlock()
+
+	s, ok := p.untracked[coord]
+	*state = s
+	return ok, nil
+}
+
+// save stores state like load reads it. When memory is full, expired
+// documents are dropped first, then arbitrary ones.
+func (p *metNoProvider) save(ctx context.Context, cityID uuid.UUID, coord string, state *metNoState) error {
+	if cityID != uuid.Nil {
+		return p.state.Save(ctx, p.Name(), cityID, state)
+	}
+
+	p.untrackedMu.Lock()
+	defer p.untrackedMu.Unlock()
+
+	if _, ok := p.untracked[coord]; !ok && len(p.untracked) >= metNoUntrackedLimit {
+		now := time.Now()
+		for k, s := range p.untracked {
+			if now.After(s.Expires) {
+				delete(p.untracked, k)
+			}
+		}
+		for k := range p.untracked {
+			if len(p.untracked) < metNoUntrackedLimit {
+				break
+			}
+			delete(p.untracked, k)
+		}
+	}
+
+	p.untracked[coord] = *state
+	return nil
+}
+
+// refresh requests document updating state, 304 keeps stored body
+func (p *metNoProvider) refresh(ctx context.Context, lat, lon string, state *metNoState) error {
+	params := url.Values{}
+	params.Set("lat", lat)
+	params.Set("lon", lon)
+
+	operation := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"?"+params.Encode(), nil)
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+
+		req.Header.Set("User-Agent", p.userAgent)
+		if state.LastModified != "" && len(state.Body) > 0 {
+			req.Header.Set("If-Modified-Since", state.LastModified)
+		}
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		switch {
+		case resp.StatusCode == http.StatusNotModified:
+		case resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNonAuthoritativeInfo:
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return err
+			}
+			state.Body = body
+			state.LastModified = resp.Header.Get("Last-Modified")
+		case resp.StatusCode == http.StatusTooManyRequests:
+			return backoff.Permanent(fmt.Errorf("rate limit exceeded"))
+		default:
+			body, _ := io.ReadAll(resp.Body)
+			err := fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+			// 403 means missing User-Agent or too precise coordinates
+			if resp.StatusCode < 500 {
+				return backoff.Permanent(err)
+			}
+			return err
+		}
+
+		state.Expires = metNoExpires(resp.Header)
+		return nil
+	}
+
+	return retryWithBackOff(ctx, operation)
+}
+
+// metNoExpires parses Expires header, without it document is reused
+// for a short time only
+func metNoExpires(h http.Header) time.Time {
+	if expires, err := http.ParseTime(h.Get("Expires")); err == nil {
+		return expires
+	}
+	return time.Now().Add(5 * time.Minute)
+}
+
+// metNoDescription turns symbol code like "lightrainshowers_day" into text
+func metNoDescription(s metNoStep) string {
+	period := s.Data.Next1Hours
+	if period == nil {
+		period = s.Data.Next6Hours
+	}
+	if period == nil {
+		return ""
+	}
+
+	code := period.Summary.SymbolCode
+	for _, suffix := range []string{"_day", "_night", "_polartwilight"} {
+		code = strings.TrimSuffix(code, suffix)
+	}
+	return strings.ReplaceAll(code, "_", " ")
+}
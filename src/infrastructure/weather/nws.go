@@ -0,0 +1,304 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/viper"
+	"math"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/infrastructure/logger"
+)
+
+const defaultNWSURL = "https://api.weather.gov"
+
+func init() {
+	RegisterProvider("nws", false, newNWSProvider, Keyless)
+}
+
+// nwsProvider uses US National Weather Service API, it covers US cities only.
+// Gridpoint and nearest station of city are resolved once and kept in StateStore.
+type nwsProvider struct {
+	client  *http.Client
+	state   StateStore
+	baseURL string
+	header  http.Header
+	// maxAge drops observations of stations which stopped reporting
+	maxAge time.Duration
+}
+
+// nwsState is resolved location of city in NWS grid
+type nwsState struct {
+	GridID      string `json:"grid_id"`
+	GridX       int    `json:"grid_x"`
+	GridY       int    `json:"grid_y"`
+	ForecastURL string `json:"forecast_url"`
+	Station     string `json:"station"`
+}
+
+// nwsValue is quantitative value, null when sensor data is missing
+type nwsValue struct {
+	Value *float64 `json:"value"`
+}
+
+func newNWSProvider(cfg *viper.Viper, client *http.Client, state StateStore) (Provider, error) {
+	cfg.SetDefault("base_url", defaultNWSURL)
+	cfg.SetDefault("max_age", 2*time.Hour)
+
+	userAgent := cfg.GetString("user_agent")
+	if userAgent == "" {
+		return nil, fmt.Errorf("user_agent is required by NWS API")
+	}
+
+	header := http.Header{}
+	header.Set("User-Agent", userAgent)
+	header.Set("Accept", "application/geo+json")
+
+	return &nwsProvider{
+		client:  client,
+		state:   state,
+		baseURL: strings.TrimSuffix(cfg.GetString("base_url"), "/"),
+		header:  header,
+		maxAge:  cfg.GetDuration("max_age"),
+	}, nil
+}
+
+func (p *nwsProvider) Name() string {
+	return "nws"
+}
+
+func (p *nwsProvider) Capabilities() Capabilities {
+	return Capabilities{
+		Current:         true,
+		Forecast:        true,
+		MaxForecastDays: 7,
+	}
+}
+
+func (p *nwsProvider) SupportsCity(city *model.City) bool {
+	return city.Country == "US"
+}
+
+func (p *nwsProvider) FetchCurrent(ctx context.Context, city *model.City, timeNow time.Time) (*model.WeatherData, error) {
+	grid, err := p.gridpoint(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Properties struct {
+			Timestamp        time.Time `json:"timestamp"`
+			Temperature      nwsValue  `json:"temperature"`
+			RelativeHumidity nwsValue  `json:"relativeHumidity"`
+			WindSpeed        nwsValue  `json:"windSpeed"`
+		} `json:"properties"`
+	}
+
+	url := fmt.Sprintf("%s/stations/%s/observations/latest", p.baseURL, grid.Station)
+	if err := getJSONWithHeader(ctx, p.client, url, p.header, &result); err != nil {
+		return nil, err
+	}
+
+	obs := result.Properties
+	if timeNow.Sub(obs.Timestamp) > p.maxAge {
+		return nil, fmt.Errorf("station %s observation is older than %s", grid.Station, p.maxAge)
+	}
+	if obs.Temperature.Value == nil || obs.RelativeHumidity.Value == nil {
+		return nil, fmt.Errorf("station %s observation is incomplete", grid.Station)
+	}
+
+	// Wind speed is reported in km/h, missing wind is calm
+	var windSpeed float64
+	if obs.WindSpeed.Value != nil {
+		windSpeed = math.Round(*obs.WindSpeed.Value/3.6*100) / 100
+	}
+
+	return &model.WeatherData{
+		CityID:      city.ID,
+		Source:      "NWS",
+		Temperature: math.Round(*obs.Temperature.Value*100) / 100,
+		Humidity:    int(math.Round(*obs.RelativeHumidity.Value)),
+		WindSpeed:   windSpeed,
+		CreatedAt:   obs.Timestamp,
+	}, nil
+}
+
+func (p *nwsProvider) FetchForecast(ctx context.Context, city *model.City, days int) ([]model.ForecastDay, error) {
+	if days < 1 || days > 7 {
+		return nil, fmt.Errorf("days must be between 1 and 7")
+	}
+
+	grid, err := p.gridpoint(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Properties struct {
+			Periods []struct {
+				StartTime        time.Time `json:"startTime"`
+				IsDaytime        bool      `json:"isDaytime"`
+				Temperature      float64   `json:"temperature"`
+				WindSpeed        string    `json:"windSpeed"`
+				RelativeHumidity nwsValue  `json:"relativeHumidity"`
+				ShortForecast    string    `json:"shortForecast"`
+			} `json:"periods"`
+		} `json:"properties"`
+	}
+
+	if err := getJSONWithHeader(ctx, p.client, grid.ForecastURL+"?units=si", p.header, &result); err != nil {
+		return nil, err
+	}
+
+	type daySum struct {
+		temperature, humidity, windSpeed float64
+		n, humidityN                     int
+		description                      string
+	}
+
+	// Periods are day and night halves in local time of the gridpoint
+	sums := make(map[time.Time]*daySum)
+	var dates []time.Time
+	for _, period := range result.Properties.Periods {
+		y, m, d := period.StartTime.Date()
+		date := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+		sum, ok := sums[date]
+		if !ok {
+			sum = &daySum{}
+			sums[date] = sum
+			dates = append(dates, date)
+		}
+
+		sum.temperature += period.Temperature
+		sum.windSpeed += nwsWindSpeed(period.WindSpeed)
+		sum.n++
+		if period.RelativeHumidity.Value != nil {
+			sum.humidity += *period.RelativeHumidity.Value
+			sum.humidityN++
+		}
+		if sum.description == "" || period.IsDaytime {
+			sum.description = period.ShortForecast
+		}
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	if len(dates) > days {
+		dates = dates[:days]
+	}
+
+	forecast := make([]model.ForecastDay, len(dates))
+	for i, date := range dates {
+		sum := sums[date]
+		n := float64(sum.n)
+
+		var humidity int
+		if sum.humidityN > 0 {
+			humidity = int(math.Round(sum.humidity / float64(sum.humidityN)))
+		}
+
+		forecast[i] = model.ForecastDay{
+			Date:        date,
+			Temperature: math.Round(sum.temperature/n*100) / 100,
+			Humidity:    humidity,
+			WindSpeed:   math.Round(sum.windSpeed/n*100) / 100,
+			Description: sum.description,
+		}
+	}
+
+	return forecast, nil
+}
+
+// gridpoint returns stored location of city or resolves it with points
+// and observation stations requests
+func (p *nwsProvider) gridpoint(ctx context.Context, city *model.City) (*nwsState, error) {
+	var state nwsState
+	found, err := p.state.Load(ctx, p.Name(), city.ID, &state)
+	if err != nil {
+		logger.Ctx(ctx).Warn().Err(err).Str("provider", p.Name()).Msg("provider.state_load_failed")
+	}
+	if found && err == nil && state.ForecastURL != "" && state.Station != "" {
+		return &state, nil
+	}
+
+	coord := city.Coord()
+
+	var point struct {
+		Properties struct {
+			GridID              string `json:"gridId"`
+			GridX               int    `json:"gridX"`
+			GridY               int    `json:"gridY"`
+			Forecast            string `json:"forecast"`
+			ObservationStations string `json:"observationStations"`
+		} `json:"properties"`
+	}
+
+	url := fmt.Sprintf("%s/points/%s,%s", p.baseURL,
+		strconv.FormatFloat(coord.Lat, 'f', 4, 64), strconv.FormatFloat(coord.Lon, 'f', 4, 64))
+	if err := getJSONWithHeader(ctx, p.client, url, p.header, &point); err != nil {
+		return nil, fmt.Errorf("resolve gridpoint: %w", err)
+	}
+	if point.Properties.Forecast == "" || point.Properties.ObservationStations == "" {
+		return nil, fmt.Errorf("location is not covered by NWS grid")
+	}
+
+	// Stations are listed by distance from gridpoint
+	var stations struct {
+		Features []struct {
+			Properties struct {
+				StationIdentifier string `json:"stationIdentifier"`
+			} `json:"properties"`
+		} `json:"features"`
+	}
+
+	if err := getJSONWithHeader(ctx, p.client, point.Properties.ObservationStations+"?limit=1", p.header, &stations); err != nil {
+		return nil, fmt.Errorf("resolve observation station: %w", err)
+	}
+	if len(stations.Features) == 0 {
+		return nil, fmt.Errorf("no observation stations near %s", city.Name)
+	}
+
+	state = nwsState{
+		GridID:      point.Properties.GridID,
+		GridX:       point.Properties.GridX,
+		GridY:       point.Properties.GridY,
+		ForecastURL: point.Properties.Forecast,
+		Station:     stations.Features[0].Properties.StationIdentifier,
+	}
+
+	if err := p.state.Save(ctx, p.Name(), city.ID, &state); err != nil {
+		logger.Ctx(ctx).Warn().Err(err).Str("provider", p.Name()).Msg("provider.state_save_failed")
+	}
+
+	logger.Ctx(ctx).Info().
+		Str("provider", p.Name()).
+		Str("grid", fmt.Sprintf("%s/%d,%d", state.GridID, state.GridX, state.GridY)).
+		Str("station", state.Station).
+		Msg("provider.gridpoint_resolved")
+
+	return &state, nil
+}
+
+var nwsWindPattern = regexp.MustCompile(`[\d.]+`)
+
+// nwsWindSpeed converts forecast wind like "10 to 20 km/h" to m/s, upper value is used
+func nwsWindSpeed(s string) float64 {
+	numbers := nwsWindPattern.FindAllString(s, -1)
+	if len(numbers) == 0 {
+		return 0
+	}
+
+	speed, err := strconv.ParseFloat(numbers[len(numbers)-1], 64)
+	if err != nil {
+		return 0
+	}
+	if strings.Contains(s, "mph") {
+		return speed * 0.44704
+	}
+	return speed / 3.6
+}
@@ -0,0 +1,109 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"weather-data-aggregator-service/src/domain/model"
+)
+
+func TestNWSWindSpeed(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+	}{
+		{in: "18 km/h", want: 5},
+		{in: "10 to 20 km/h", want: 5.56},
+		{in: "10 mph", want: 4.47},
+		{in: "5 to 15 mph", want: 6.71},
+		{in: "7.2 km/h", want: 2},
+		{in: "0 km/h", want: 0},
+		{in: "", want: 0},
+		{in: "calm", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got := math.Round(nwsWindSpeed(tt.in)*100) / 100
+			if got != tt.want {
+				t.Errorf("nwsWindSpeed(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// stationState is StateStore with resolved gridpoint of every city
+type stationState struct{}
+
+func (stationState) Load(_ context.Context, _ string, _ uuid.UUID, out interface{}) (bool, error) {
+	*out.(*nwsState) = nwsState{ForecastURL: "unused", Station: "KNYC"}
+	return true, nil
+}
+
+func (stationState) Save(context.Context, string, uuid.UUID, interface{}) error {
+	return nil
+}
+
+func TestNWSFetchCurrentObservationTime(t *testing.T) {
+	timeNow := time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		timestamp string
+		wantErr   bool
+	}{
+		{name: "recent observation", timestamp: "2025-01-01T11:51:00+00:00"},
+		{name: "stale observation", timestamp: "2025-01-01T08:51:00+00:00", wantErr: true},
+		{name: "missing timestamp", timestamp: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/stations/KNYC/observations/latest" {
+					http.NotFound(w, r)
+					return
+				}
+				var timestamp interface{}
+				if tt.timestamp != "" {
+					timestamp = tt.timestamp
+				}
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"properties": map[string]interface{}{
+						"timestamp":        timestamp,
+						"temperature":      map[string]interface{}{"value": 3.3},
+						"relativeHumidity": map[string]interface{}{"value": 71.5},
+						"windSpeed":        map[string]interface{}{"value": 18},
+					},
+				})
+			}))
+			defer srv.Close()
+
+			p := &nwsProvider{client: srv.Client(), state: stationState{}, baseURL: srv.URL, maxAge: 2 * time.Hour}
+
+			data, err := p.FetchCurrent(context.Background(), &model.City{Name: "New York", Country: "US"}, timeNow)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("FetchCurrent() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchCurrent() error = %v", err)
+			}
+
+			want, _ := time.Parse(time.RFC3339, tt.timestamp)
+			if !data.CreatedAt.Equal(want) {
+				t.Errorf("CreatedAt = %s, want observation time %s", data.CreatedAt, want)
+			}
+			if data.Temperature != 3.3 || data.Humidity != 72 || data.WindSpeed != 5 {
+				t.Errorf("data = %+v", data)
+			}
+		})
+	}
+}
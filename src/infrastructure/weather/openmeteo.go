@@ -0,0 +1,224 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/viper"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+	"weather-data-aggregator-service/src/domain/model"
+)
+
+const defaultOpenMeteoURL = "https://api.open-meteo.com/v1/forecast"
+
+// openMeteoTimeLayout is time format of Open-Meteo with timezone=UTC
+const openMeteoTimeLayout = "2006-01-02T15:04"
+
+func init() {
+	RegisterProvider("openmeteo", true, newOpenMeteoProvider, Keyless)
+}
+
+// openMeteoProvider needs no API key, so it is enabled by default as fallback
+type openMeteoProvider struct {
+	client  *http.Client
+	baseURL string
+}
+
+func newOpenMeteoProvider(cfg *viper.Viper, client *http.Client, _ StateStore) (Provider, error) {
+	cfg.SetDefault("base_url", defaultOpenMeteoURL)
+
+	return &openMeteoProvider{
+		client:  client,
+		baseURL: cfg.GetString("base_url"),
+	}, nil
+}
+
+func (p *openMeteoProvider) Name() string {
+	return "openmeteo"
+}
+
+func (p *openMeteoProvider) Capabilities() Capabilities {
+	return Capabilities{
+		Current:          true,
+		Forecast:         true,
+		MaxForecastDays:  16,
+		MaxForecastHours: 16 * 24,
+	}
+}
+
+func (p *openMeteoProvider) FetchCurrent(ctx context.Context, city *model.City, timeNow time.Time) (*model.WeatherData, error) {
+	params := p.params(city)
+	params.Set("current", "temperature_2m,relative_humidity_2m,wind_speed_10m,weather_code")
+
+	var result struct {
+		Current struct {
+			Temperature float64 `json:"temperature_2m"`
+			Humidity    float64 `json:"relative_humidity_2m"`
+			WindSpeed   float64 `json:"wind_speed_10m"`
+		} `json:"current"`
+	}
+
+	if err := getJSON(ctx, p.client, p.baseURL+"?"+params.Encode(), &result); err != nil {
+		return nil, err
+	}
+
+	return &model.WeatherData{
+		CityID:      city.ID,
+		Source:      "Open-Meteo",
+		Temperature: result.Current.Temperature,
+		Humidity:    int(math.Round(result.Current.Humidity)),
+		WindSpeed:   math.Round(result.Current.WindSpeed*100) / 100,
+		CreatedAt:   timeNow,
+	}, nil
+}
+
+func (p *openMeteoProvider) FetchForecast(ctx context.Context, city *model.City, days int) ([]model.ForecastDay, error) {
+	if days < 1 || days > 16 {
+		return nil, fmt.Errorf("days must be between 1 and 16")
+	}
+
+	params := p.params(city)
+	params.Set("daily", "temperature_2m_max,temperature_2m_min,relative_humidity_2m_mean,wind_speed_10m_max,weather_code")
+	params.Set("forecast_days", strconv.Itoa(days))
+
+	var result struct {
+		Daily struct {
+			Time           []string  `json:"time"`
+			TemperatureMax []float64 `json:"temperature_2m_max"`
+			TemperatureMin []float64 `json:"temperature_2m_min"`
+			Humidity       []float64 `json:"relative_humidity_2m_mean"`
+			WindSpeedMax   []float64 `json:"wind_speed_10m_max"`
+			WeatherCode    []int     `json:"weather_code"`
+		} `json:"daily"`
+	}
+
+	if err := getJSON(ctx, p.client, p.baseURL+"?"+params.Encode(), &result); err != nil {
+		return nil, err
+	}
+
+	d := result.Daily
+	n := len(d.Time)
+	if len(d.TemperatureMax) < n || len(d.TemperatureMin) < n || len(d.Humidity) < n ||
+		len(d.WindSpeedMax) < n || len(d.WeatherCode) < n {
+		return nil, fmt.Errorf("inconsistent daily forecast")
+	}
+
+	forecast := make([]model.ForecastDay, n)
+	for i := range forecast {
+		date, err := time.Parse("2006-01-02", d.Time[i])
+		if err != nil {
+			return nil, fmt.Errorf("parse forecast date: %w", err)
+		}
+
+		forecast[i] = model.ForecastDay{
+			Date:        date,
+			Temperature: math.Round((d.TemperatureMax[i]+d.TemperatureMin[i])/2*100) / 100,
+			Humidity:    int(math.Round(d.Humidity[i])),
+			WindSpeed:   d.WindSpeedMax[i],
+			Description: wmoDescription(d.WeatherCode[i]),
+		}
+	}
+
+	return forecast, nil
+}
+
+func (p *openMeteoProvider) FetchHourly(ctx context.Context, city *model.City, hours int) ([]model.ForecastHour, error) {
+	if hours < 1 || hours > 16*24 {
+		return nil, fmt.Errorf("hours must be between 1 and %d", 16*24)
+	}
+
+	params := p.params(city)
+	params.Set("hourly", "temperature_2m,relative_humidity_2m,wind_speed_10m,weather_code")
+	params.Set("forecast_hours", strconv.Itoa(hours))
+
+	var result struct {
+		Hourly struct {
+			Time        []string  `json:"time"`
+			Temperature []float64 `json:"temperature_2m"`
+			Humidity    []float64 `json:"relative_humidity_2m"`
+			WindSpeed   []float64 `json:"wind_speed_10m"`
+			WeatherCode []int     `json:"weather_code"`
+		} `json:"hourly"`
+	}
+
+	if err := getJSON(ctx, p.client, p.baseURL+"?"+params.Encode(), &result); err != nil {
+		return nil, err
+	}
+
+	h := result.Hourly
+	n := len(h.Time)
+	if len(h.Temperature) < n || len(h.Humidity) < n || len(h.WindSpeed) < n || len(h.WeatherCode) < n {
+		return nil, fmt.Errorf("inconsistent hourly forecast")
+	}
+
+	forecast := make([]model.ForecastHour, n)
+	for i := range forecast {
+		t, err := time.Parse(openMeteoTimeLayout, h.Time[i])
+		if err != nil {
+			return nil, fmt.Errorf("parse forecast time: %w", err)
+		}
+
+		forecast[i] = model.ForecastHour{
+			Time:        t,
+			Temperature: h.Temperature[i],
+			Humidity:    int(math.Round(h.Humidity[i])),
+			WindSpeed:   h.WindSpeed[i],
+			Description: wmoDescription(h.WeatherCode[i]),
+		}
+	}
+
+	return forecast, nil
+}
+
+// params are common query parameters, wind speed in m/s like other providers
+func (p *openMeteoProvider) params(city *model.City) url.Values {
+	coord := city.Coord()
+
+	params := url.Values{}
+	params.Set("latitude", strconv.FormatFloat(coord.Lat, 'f', 4, 64))
+	params.Set("longitude", strconv.FormatFloat(coord.Lon, 'f', 4, 64))
+	params.Set("wind_speed_unit", "ms")
+	params.Set("timezone", "UTC")
+	return params
+}
+
+// wmoDescription describes WMO weather interpretation code used by Open-Meteo
+func wmoDescription(code int) string {
+	switch code {
+	case 0:
+		return "clear sky"
+	case 1:
+		return "mainly clear"
+	case 2:
+		return "partly cloudy"
+	case 3:
+		return "overcast"
+	case 45, 48:
+		return "fog"
+	case 51, 53, 55:
+		return "drizzle"
+	case 56, 57:
+		return "freezing drizzle"
+	case 61, 63, 65:
+		return "rain"
+	case 66, 67:
+		return "freezing rain"
+	case 71, 73, 75:
+		return "snow fall"
+	case 77:
+		return "snow grains"
+	case 80, 81, 82:
+		return "rain showers"
+	case 85, 86:
+		return "snow showers"
+	case 95:
+		return "thunderstorm"
+	case 96, 99:
+		return "thunderstorm with hail"
+	default:
+		return fmt.Sprintf("weather code %d", code)
+	}
+}
@@ -0,0 +1,120 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/viper"
+	"math"
+	"net/http"
+	"time"
+	"weather-data-aggregator-service/src/domain/model"
+)
+
+func init() {
+	RegisterProvider("openweather", true, newOpenWeatherProvider, LegacyKey("open_weather.key"))
+}
+
+type openWeatherProvider struct {
+	client  *http.Client
+	apiKey  string
+	oneCall bool
+}
+
+func newOpenWeatherProvider(cfg *viper.Viper, client *http.Client, _ StateStore) (Provider, error) {
+	key := cfg.GetString("key")
+	if key == "" {
+		return nil, fmt.Errorf("key is required")
+	}
+
+	return &openWeatherProvider{
+		client: client,
+		apiKey: key,
+		// One Call API is a paid service, so forecast is opt-in
+		oneCall: cfg.GetBool("one_call"),
+	}, nil
+}
+
+func (p *openWeatherProvider) Name() string {
+	return "openweather"
+}
+
+func (p *openWeatherProvider) Capabilities() Capabilities {
+	return Capabilities{
+		Current:         true,
+		Forecast:        p.oneCall,
+		MaxForecastDays: 7,
+	}
+}
+
+func (p *openWeatherProvider) FetchCurrent(ctx context.Context, city *model.City, timeNow time.Time) (*model.WeatherData, error) {
+	coord := city.Coord()
+	url := fmt.Sprintf(
+		"https://api.openweathermap.org/data/2.5/weather?lat=%f&lon=%f&appid=%s&units=metric",
+		coord.Lat, coord.Lon, p.apiKey,
+	)
+
+	var result struct {
+		Main struct {
+			Temp     float64 `json:"temp"`
+			Humidity int     `json:"humidity"`
+		} `json:"main"`
+		Wind struct {
+			Speed float64 `json:"speed"`
+		} `json:"wind"`
+		Weather []struct {
+			Description string `json:"description"`
+		} `json:"weather"`
+	}
+
+	if err := getJSON(ctx, p.client, url, &result); err != nil {
+		return nil, err
+	}
+
+	return &model.WeatherData{
+		CityID:      city.ID,
+		Source:      "OpenWeatherMap",
+		Temperature: result.Main.Temp,
+		Humidity:    result.Main.Humidity,
+		WindSpeed:   math.Round(result.Wind.Speed*100) / 100,
+		CreatedAt:   timeNow,
+	}, nil
+}
+
+func (p *openWeatherProvider) FetchForecast(ctx context.Context, city *model.City, days int) ([]model.ForecastDay, error) {
+	if days < 1 || days > 7 {
+		return nil, fmt.Errorf("days must be between 1 and 7")
+	}
+
+	coord := city.Coord()
+	oneCallURL := fmt.Sprintf(
+		"https://api.openweathermap.org/data/2.5/onecall?lat=%f&lon=%f&exclude=minutely,hourly,alerts,current&units=metric&appid=%s",
+		coord.Lat, coord.Lon, p.apiKey,
+	)
+
+	var oneCallResp model.OneCallRespOWM
+	if err := getJSON(ctx, p.client, oneCallURL, &oneCallResp); err != nil {
+		return nil, err
+	}
+
+	if len(oneCallResp.Daily) < days {
+		days = len(oneCallResp.Daily)
+	}
+
+	result := make([]model.ForecastDay, days)
+	for i := 0; i < days; i++ {
+		d := oneCallResp.Daily[i]
+		desc := ""
+		if len(d.Weather) > 0 {
+			desc = d.Weather[0].Description
+		}
+		result[i] = model.ForecastDay{
+			Date:        time.Unix(d.Dt, 0),
+			Temperature: d.Temp.Day,
+			Humidity:    d.Humidity,
+			WindSpeed:   d.WindSpeed,
+			Description: desc,
+		}
+	}
+
+	return result, nil
+}
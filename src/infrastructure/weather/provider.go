@@ -0,0 +1,221 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"github.com/sony/gobreaker"
+	"github.com/spf13/viper"
+	"net/http"
+	"sort"
+	"time"
+	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/infrastructure/metrics"
+)
+
+// Capabilities describes what kind of data a provider is able to return
+type Capabilities struct {
+	Current         bool
+	Forecast        bool
+	MaxForecastDays int
+	// MaxForecastHours is set by providers implementing HourlyProvider
+	MaxForecastHours int
+}
+
+// Provider represent weather source contract
+type Provider interface {
+	// Name returns provider key used in config, logs and circuit breaker
+	Name() string
+	FetchCurrent(ctx context.Context, city *model.City, timeNow time.Time) (*model.WeatherData, error)
+	FetchForecast(ctx context.Context, city *model.City, days int) ([]model.ForecastDay, error)
+	Capabilities() Capabilities
+}
+
+// HourlyProvider is implemented by providers with hourly forecast
+type HourlyProvider interface {
+	FetchHourly(ctx context.Context, city *model.City, hours int) ([]model.ForecastHour, error)
+}
+
+// CityProvider is implemented by providers covering only some cities,
+// others are not requested and not counted as missing
+type CityProvider interface {
+	SupportsCity(city *model.City) bool
+}
+
+// ProviderFactory builds provider from its "providers.<name>" config section,
+// client and state store are shared by all providers
+type ProviderFactory func(cfg *viper.Viper, client *http.Client, state StateStore) (Provider, error)
+
+type providerSpec struct {
+	name           string
+	defaultEnabled bool
+	keyless        bool
+	legacyKey      string
+	factory        ProviderFactory
+}
+
+// ProviderOption adjusts provider registration
+type ProviderOption func(s *providerSpec)
+
+// Keyless marks provider which needs no API key, only such providers
+// are loaded by public-facing API instances
+func Keyless(s *providerSpec) {
+	s.keyless = true
+}
+
+// LegacyKey reads API key from setting used before "providers.<name>.key",
+// so existing configs keep working
+func LegacyKey(setting string) ProviderOption {
+	return func(s *providerSpec) {
+		s.legacyKey = setting
+	}
+}
+
+// providerEntry is enabled provider with its own circuit breaker
+// and optional limit of concurrent requests
+type providerEntry struct {
+	Provider
+	cb      *gobreaker.CircuitBreaker
+	sem     chan struct{}
+	keyless bool
+}
+
+func (p *providerEntry) acquire(ctx context.Context) error {
+	if p.sem == nil {
+		return nil
+	}
+
+	select {
+	case p.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *providerEntry) release() {
+	if p.sem != nil {
+		<-p.sem
+	}
+}
+
+func (p *providerEntry) covers(city *model.City) bool {
+	if cp, ok := p.Provider.(CityProvider); ok {
+		return cp.SupportsCity(city)
+	}
+	return true
+}
+
+var providerSpecs = map[string]providerSpec{}
+
+// RegisterProvider makes provider available for enabling from config
+func RegisterProvider(name string, defaultEnabled bool, factory ProviderFactory, opts ...ProviderOption) {
+	if _, ok := providerSpecs[name]; ok {
+		panic(fmt.Sprintf("weather provider %q already registered", name))
+	}
+
+	spec := providerSpec{
+		name:           name,
+		defaultEnabled: defaultEnabled,
+		factory:        factory,
+	}
+	for _, opt := range opts {
+		opt(&spec)
+	}
+
+	providerSpecs[name] = spec
+}
+
+// loadProviders creates all providers enabled in config, sorted by name.
+// Providers needing API key are skipped when keylessOnly is set.
+func loadProviders(client *http.Client, state StateStore, keylessOnly bool) []*providerEntry {
+	names := make([]string, 0, len(providerSpecs))
+	for name := range providerSpecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var entries []*providerEntry
+	for _, name := range names {
+		spec := providerSpecs[name]
+
+		cfg := viper.Sub("providers." + name)
+		if cfg == nil {
+			cfg = viper.New()
+		}
+		cfg.SetDefault("enabled", spec.defaultEnabled)
+
+		if spec.legacyKey != "" && cfg.GetString("key") == "" && viper.GetString(spec.legacyKey) != "" {
+			log.Warn().Str("provider", name).
+				Str("setting", spec.legacyKey).
+				Str("replacement", "providers."+name+".key").
+				Msg("provider.legacy_key_deprecated")
+			cfg.Set("key", viper.GetString(spec.legacyKey))
+		}
+
+		if !cfg.GetBool("enabled") {
+			log.Info().Str("provider", name).Msg("provider.disabled")
+			continue
+		}
+		if keylessOnly && !spec.keyless {
+			log.Info().Str("provider", name).Msg("provider.skipped_keyed")
+			continue
+		}
+
+		p, err := spec.factory(cfg, client, state)
+		if err != nil {
+			log.Error().Err(err).Str("provider", name).Msg("provider.create_failed")
+			continue
+		}
+
+		metrics.SetBreakerState(name, gobreaker.StateClosed)
+
+		entry := &providerEntry{
+			Provider: p,
+			cb:       newCircuitBreaker(name),
+			keyless:  spec.keyless,
+		}
+		if limit := cfg.GetInt("max_concurrency"); limit > 0 {
+			entry.sem = make(chan struct{}, limit)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if len(entries) == 0 {
+		log.Error().Msg("provider.none_enabled")
+	}
+
+	return entries
+}
+
+// BreakerOpen is circuit breaker state when provider requests are rejected
+var BreakerOpen = gobreaker.StateOpen.String()
+
+// BreakerStates returns circuit breaker state of every enabled provider
+func (w *WeatherClient) BreakerStates() map[string]string {
+	states := make(map[string]string, len(w.providers))
+	for _, p := range w.providers {
+		states[p.Name()] = p.cb.State().String()
+	}
+	return states
+}
+
+func newCircuitBreaker(name string) *gobreaker.CircuitBreaker {
+	return gobreaker.NewCircuitBreaker(gobreaker.Settings{
+		Name:        name,
+		MaxRequests: 1,
+		Interval:    30 * time.Second,
+		Timeout:     5 * time.Second,
+		OnStateChange: func(name string, from, to gobreaker.State) {
+			metrics.SetBreakerState(name, to)
+		},
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			if counts.Requests < 10 {
+				return false
+			}
+			failureRate := float64(counts.TotalFailures) / float64(counts.Requests)
+			return failureRate > 0.5
+		},
+	})
+}
@@ -0,0 +1,58 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/viper"
+	"math"
+	"time"
+	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/infrastructure/logger"
+)
+
+// stationReading collapses latest readings of personal weather stations of
+// city pushed within pws.max_age into median one. Stations are single "pws"
+// source for quorum and weighting however many of them report. Nil means no
+// station has reported.
+func (w *WeatherClient) stationReading(ctx context.Context, city *model.City, timeNow time.Time) *reading {
+	viper.SetDefault("pws.max_age", 15*time.Minute)
+
+	var latest []model.WeatherData
+	err := w.dbClient.NewSelect().Model(&latest).
+		DistinctOn("source").
+		Where("city_id = ?", city.ID).
+		Where("source_type = ?", model.SourceTypePWS).
+		Where("created_at >= ?", timeNow.Add(-viper.GetDuration("pws.max_age"))).
+		OrderExpr("source, created_at DESC").
+		Scan(ctx)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("pws.readings_failed")
+		return nil
+	}
+	if len(latest) == 0 {
+		return nil
+	}
+
+	temperature := make([]float64, len(latest))
+	humidity := make([]float64, len(latest))
+	windSpeed := make([]float64, len(latest))
+	var createdAt time.Time
+	for i, data := range latest {
+		temperature[i] = data.Temperature
+		humidity[i] = float64(data.Humidity)
+		windSpeed[i] = data.WindSpeed
+		if data.CreatedAt.After(createdAt) {
+			createdAt = data.CreatedAt
+		}
+	}
+
+	return &reading{provider: "pws", data: &model.WeatherData{
+		CityID:      city.ID,
+		Source:      fmt.Sprintf("PWS (%d)", len(latest)),
+		SourceType:  model.SourceTypePWS,
+		Temperature: math.Round(median(temperature)*100) / 100,
+		Humidity:    int(math.Round(median(humidity))),
+		WindSpeed:   math.Round(median(windSpeed)*100) / 100,
+		CreatedAt:   createdAt,
+	}}
+}
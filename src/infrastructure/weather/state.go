@@ -0,0 +1,73 @@
+package weather
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"time"
+	"weather-data-aggregator-service/src/domain/model"
+)
+
+// StateStore persists provider data per city, so it survives restarts
+// and is shared by instances. Untracked locations (nil city ID) have no state.
+type StateStore interface {
+	// Load decodes state into out, false if there is no state yet
+	Load(ctx context.Context, provider string, cityID uuid.UUID, out interface{}) (bool, error)
+	Save(ctx context.Context, provider string, cityID uuid.UUID, state interface{}) error
+}
+
+type postgresStateStore struct {
+	db *bun.DB
+}
+
+func newStateStore(db *bun.DB) StateStore {
+	return &postgresStateStore{db}
+}
+
+func (s *postgresStateStore) Load(ctx context.Context, provider string, cityID uuid.UUID, out interface{}) (bool, error) {
+	if cityID == uuid.Nil {
+		return false, nil
+	}
+
+	var state model.ProviderState
+	err := s.db.NewSelect().Model(&state).
+		Where("provider = ?", provider).
+		Where("city_id = ?", cityID).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, json.Unmarshal(state.State, out)
+}
+
+func (s *postgresStateStore) Save(ctx context.Context, provider string, cityID uuid.UUID, v interface{}) error {
+	if cityID == uuid.Nil {
+		return nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	state := &model.ProviderState{
+		Provider:  provider,
+		CityID:    cityID,
+		State:     data,
+		UpdatedAt: time.Now(),
+	}
+
+	_, err = s.db.NewInsert().Model(state).
+		On("CONFLICT (provider, city_id) DO UPDATE").
+		Set("state = EXCLUDED.state").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	return err
+}
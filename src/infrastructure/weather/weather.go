@@ -3,281 +3,596 @@ package weather
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/cenk/backoff"
-	"github.com/gofiber/fiber/v2/log"
-	"github.com/google/uuid"
-	"github.com/sony/gobreaker"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
 	"github.com/uptrace/bun"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
 	"io"
-	"math"
 	"net/http"
 	"net/url"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/infrastructure/logger"
+	"weather-data-aggregator-service/src/infrastructure/metrics"
+	"weather-data-aggregator-service/src/infrastructure/tracing"
 )
 
-// API Clients with circuit breakers
-var (
-	openWeatherCB = gobreaker.NewCircuitBreaker(gobreaker.Settings{
-		Name:        "openweather",
-		MaxRequests: 1,
-		Interval:    30 * time.Second,
-		Timeout:     5 * time.Second,
-		ReadyToTrip: func(counts gobreaker.Counts) bool {
-			if counts.Requests < 10 {
-				return false
-			}
-			failureRate := float64(counts.TotalFailures) / float64(counts.Requests)
-			return failureRate > 0.5
-		},
-	})
+type retriesKey struct{}
 
-	weatherAPICB = gobreaker.NewCircuitBreaker(gobreaker.Settings{
-		Name:        "weatherapi",
-		MaxRequests: 1,
-		Interval:    30 * time.Second,
-		Timeout:     5 * time.Second,
-		ReadyToTrip: func(counts gobreaker.Counts) bool {
-			if counts.Requests < 10 {
-				return false
-			}
-			failureRate := float64(counts.TotalFailures) / float64(counts.Requests)
-			return failureRate > 0.5
-		},
+// newBackOff returns retry policy of single provider request, it gives up
+// after http_client.retries retries or http_client.retry_max_elapsed.
+// Retries are counted into counter of withRetries ctx.
+func newBackOff(ctx context.Context) backoff.BackOff {
+	viper.SetDefault("http_client.retries", 2)
+	viper.SetDefault("http_client.retry_max_elapsed", 5*time.Second)
+
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = 300 * time.Millisecond
+	b.MaxElapsedTime = viper.GetDuration("http_client.retry_max_elapsed")
+
+	return backoff.WithContext(backoff.WithMaxRetries(b, uint64(viper.GetInt("http_client.retries"))), ctx)
+}
+
+// withRetries returns ctx counting retries of requests made with it
+func withRetries(ctx context.Context) (context.Context, *atomic.Int64) {
+	counter := &atomic.Int64{}
+	return context.WithValue(ctx, retriesKey{}, counter), counter
+}
+
+// retryWithBackOff runs operation with newBackOff policy,
+// every try gets its own "provider.attempt" span
+func retryWithBackOff(ctx context.Context, operation func(ctx context.Context) error) error {
+	tries := 0
+	try := func() error {
+		tries++
+		ctx, span := tracing.Start(ctx, "provider.attempt", attribute.Int("weather.attempt", tries))
+		err := operation(ctx)
+		tracing.End(span, err)
+		return err
+	}
+
+	return backoff.RetryNotify(try, newBackOff(ctx), func(err error, wait time.Duration) {
+		if counter, ok := ctx.Value(retriesKey{}).(*atomic.Int64); ok {
+			counter.Add(1)
+		}
+		logger.Ctx(ctx).Debug().Err(err).Dur("wait", wait).Msg("provider.request_retry")
 	})
-)
+}
+
+// newHTTPClient creates client shared by providers with http_client.timeout
+func newHTTPClient() *http.Client {
+	viper.SetDefault("http_client.timeout", 10*time.Second)
+
+	return &http.Client{
+		Timeout: viper.GetDuration("http_client.timeout"),
+		Transport: otelhttp.NewTransport(&http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			MaxIdleConns:          100,
+			MaxIdleConnsPerHost:   10,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   5 * time.Second,
+			ResponseHeaderTimeout: 10 * time.Second,
+		}),
+	}
+}
 
-// Generic retry wrapper
-func retry(attempts int, base time.Duration, fn func() error) error {
-	var err error
-	delay := base
+// getJSON requests url with exponential backoff and decodes JSON body into out
+func getJSON(ctx context.Context, client *http.Client, url string, out interface{}) error {
+	return getJSONWithHeader(ctx, client, url, nil, out)
+}
 
-	for i := 0; i < attempts; i++ {
-		if err = fn(); err == nil {
-			return nil
+// getJSONWithHeader is getJSON sending additional request headers
+func getJSONWithHeader(ctx context.Context, client *http.Client, url string, header http.Header, out interface{}) error {
+	var resp *http.Response
+
+	operation := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return backoff.Permanent(withoutQuery(err))
 		}
-		time.Sleep(delay)
-		delay *= 2
+		for k, v := range header {
+			req.Header[k] = v
+		}
+
+		r, err := client.Do(req)
+		if err != nil {
+			return withoutQuery(err)
+		}
+
+		if r.StatusCode == http.StatusTooManyRequests {
+			r.Body.Close()
+			return backoff.Permanent(fmt.Errorf("rate limit exceeded"))
+		}
+
+		if r.StatusCode >= 400 {
+			body, _ := io.ReadAll(r.Body)
+			r.Body.Close()
+			err := fmt.Errorf("HTTP %d: %s", r.StatusCode, string(body))
+			// Client errors (unknown city, bad key) will not fix themselves
+			if r.StatusCode < 500 {
+				return backoff.Permanent(err)
+			}
+			return err
+		}
+
+		resp = r
+		return nil
 	}
-	return err
+
+	if err := retryWithBackOff(ctx, operation); err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(out)
 }
 
-func (w *WeatherClient) fetchWeatherData() {
-	for _, city := range w.cities {
-		w.fetchCityWeather(&city)
+// withoutQuery drops query from URL of transport error, some providers
+// pass API key in it and errors are stored in fetch journal and traced
+func withoutQuery(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		if i := strings.IndexByte(urlErr.URL, '?'); i >= 0 {
+			urlErr.URL = urlErr.URL[:i]
+		}
 	}
+	return err
 }
 
-func (w *WeatherClient) fetchCityWeather(city *model.City) {
-	log.Info("Fetching weather for city: %s", city.Name)
+func (w *WeatherClient) fetchCityWeather(ctx context.Context, run *model.FetchRun, city *model.City) (status fetchStatus) {
+	ctx, span := tracing.Start(ctx, "weather.fetchCity", attribute.String("weather.city", city.Name))
+	defer func() {
+		span.SetAttributes(attribute.String("weather.fetch.status", status.String()))
+		span.End()
+	}()
 
-	var wg sync.WaitGroup
-	wg.Add(2)
+	if !city.HasCoord() {
+		log.Error().Str("city", city.Name).Msg("ingestion.city_location_unresolved")
+		return fetchSkipped
+	}
+
+	ctx = logger.With(ctx, "city", city.Name)
+	logger.Ctx(ctx).Debug().Msg("ingestion.city_fetch_started")
 
 	timeNow := time.Now()
+	readings, missing, attempts := w.fetchCurrent(ctx, city, timeNow, false)
 
-	var (
-		openWeatherData model.WeatherData
-		weatherAPIData  model.WeatherData
-	)
+	// Observation is stored with provider data but is not aggregated
+	observation, attempt := w.fetchObservation(ctx, city, timeNow)
+	if attempt != nil {
+		attempts = append(attempts, *attempt)
+	}
+	w.recordAttempts(ctx, run, city, attempts)
 
-	// OpenWeather Fetch
-	go func() {
-		defer wg.Done()
+	fetched := make([]*model.WeatherData, 0, len(readings)+1)
+	for _, r := range readings {
+		fetched = append(fetched, r.data)
+	}
+	if observation != nil {
+		fetched = append(fetched, observation)
+	}
 
-		_, err := openWeatherCB.Execute(func() (interface{}, error) {
-			return nil, retry(3, 300*time.Millisecond, func() error {
-				return fetchOpenWeather(&openWeatherData, city, w.openWeatherAPIKey, timeNow)
-			})
-		})
+	// Station readings are aggregated too, they were stored when pushed
+	if r := w.stationReading(ctx, city, timeNow); r != nil {
+		readings = append(readings, *r)
+	}
 
-		if err != nil {
-			log.Errorf("[ERROR] OpenWeather fetch failed for %s (CB=%v): %v",
-				city, openWeatherCB.State(), err)
-			return
+	if len(readings) == 0 {
+		logger.Ctx(ctx).Error().Msg("ingestion.city_no_data")
+		if len(fetched) > 0 {
+			if err := w.saveCityWeather(ctx, fetched, nil); err != nil {
+				logger.Ctx(ctx).Error().Err(err).Msg("ingestion.city_save_failed")
+			}
 		}
-	}()
+		return fetchFailed
+	}
 
-	// WeatherAPI Fetch
-	go func() {
-		defer wg.Done()
+	aggregated := w.aggregator.Aggregate(city, readings, missing)
 
-		_, err := weatherAPICB.Execute(func() (interface{}, error) {
-			return nil, retry(3, 300*time.Millisecond, func() error {
-				return fetchWeatherAPI(&weatherAPIData, city, w.weatherAPIKey, timeNow)
-			})
-		})
+	if len(readings) < w.quorum {
+		logger.Ctx(ctx).Error().Int("sources", len(readings)).Int("quorum", w.quorum).Msg("ingestion.city_quorum_not_reached")
+		aggregated = nil
+	} else if missing > 0 {
+		logger.Ctx(ctx).Warn().Strs("sources", aggregated.Sources).Int("missing", missing).Msg("ingestion.city_aggregated_partially")
+	}
 
-		if err != nil {
-			log.Errorf("[ERROR] WeatherAPI fetch failed for %s (CB=%v): %v",
-				city.Name, weatherAPICB.State(), err)
-			return
-		}
-	}()
+	if err := w.saveCityWeather(ctx, fetched, aggregated); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("ingestion.city_save_failed")
+		return fetchFailed
+	}
 
-	wg.Wait()
+	if aggregated == nil {
+		return fetchPartial
+	}
 
-	if openWeatherData.CityID != uuid.Nil && weatherAPIData.CityID != uuid.Nil {
-		ctx := context.Background()
+	metrics.CityLastUpdate.WithLabelValues(city.Name, city.Country).SetToCurrentTime()
 
-		tx, err := w.dbClient.BeginTx(ctx, nil)
-		if err != nil {
-			log.Errorf("Failed to begin transaction: %v", err)
+	for _, hook := range w.onAggregate {
+		hook(ctx, city)
+	}
+
+	if missing > 0 {
+		return fetchPartial
+	}
+	return fetchOK
+}
+
+// fetchCurrent requests current weather from every enabled provider in parallel,
+// keylessOnly leaves out providers with paid API keys. Returns readings of
+// succeeded providers, number of failed ones and journal attempt of every provider.
+func (w *WeatherClient) fetchCurrent(ctx context.Context, city *model.City, timeNow time.Time, keylessOnly bool) ([]reading, int, []model.FetchAttempt) {
+	var wg sync.WaitGroup
+
+	results := make([]*model.WeatherData, len(w.providers))
+	attempts := make([]*model.FetchAttempt, len(w.providers))
+
+	for i, p := range w.providers {
+		if !p.Capabilities().Current || !p.covers(city) || (keylessOnly && !p.keyless) {
+			continue
 		}
-		w.saveWeatherData(&tx, &openWeatherData)
-		w.saveWeatherData(&tx, &weatherAPIData)
-
-		w.saveAggregatedWeatherData(&tx, &model.AggregatedWeatherData{
-			CityID:      city.ID,
-			Temperature: (openWeatherData.Temperature + weatherAPIData.Temperature) / 2,
-			Humidity:    (openWeatherData.Humidity + weatherAPIData.Humidity) / 2,
-			WindSpeed:   math.Round(((openWeatherData.WindSpeed+weatherAPIData.WindSpeed)/2)*100) / 100,
-		})
 
-		if err := tx.Commit(); err != nil {
-			log.Errorf("Failed to commit transaction: %v", err)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			start := time.Now()
+			ctx, retries := withRetries(ctx)
+
+			ctx, span := tracing.Start(ctx, "provider.FetchCurrent",
+				attribute.String("weather.provider", p.Name()),
+				attribute.String("weather.city", city.Name),
+			)
+			ctx = logger.With(ctx, "provider", p.Name())
+
+			if err := p.acquire(ctx); err != nil {
+				attempts[i] = &model.FetchAttempt{
+					Provider: p.Name(),
+					Status:   model.FetchAttemptCancelled,
+					Error:    err.Error(),
+				}
+				tracing.End(span, err)
+				return
+			}
+			defer p.release()
+
+			// Call is bounded as whole, retries of its requests included
+			ctx, cancel := context.WithTimeout(ctx, w.callTimeout)
+			defer cancel()
+
+			res, err := p.cb.Execute(func() (interface{}, error) {
+				return p.FetchCurrent(ctx, city, timeNow)
+			})
+
+			attempts[i] = &model.FetchAttempt{
+				Provider:     p.Name(),
+				Status:       attemptStatus(ctx, err),
+				LatencyMs:    time.Since(start).Milliseconds(),
+				CircuitState: p.cb.State().String(),
+				Retries:      int(retries.Load()),
+			}
+
+			metrics.ObserveProviderRequest(p.Name(), "current", attempts[i].Status, time.Since(start))
+			metrics.ProviderRetries.WithLabelValues(p.Name()).Add(float64(attempts[i].Retries))
+
+			span.SetAttributes(
+				attribute.String("weather.attempt.status", attempts[i].Status),
+				attribute.Int("weather.attempt.retries", attempts[i].Retries),
+				attribute.String("weather.circuit_state", attempts[i].CircuitState),
+			)
+			tracing.End(span, err)
+
+			if err != nil {
+				attempts[i].Error = err.Error()
+				logger.Ctx(ctx).Error().Err(err).
+					Str("status", attempts[i].Status).
+					Str("circuit_state", attempts[i].CircuitState).
+					Msg("provider.current_failed")
+				return
+			}
+
+			results[i] = res.(*model.WeatherData)
+		}()
+	}
+
+	wg.Wait()
+
+	var (
+		readings []reading
+		missing  int
+		journal  []model.FetchAttempt
+	)
+	for i, p := range w.providers {
+		if !p.Capabilities().Current || !p.covers(city) || (keylessOnly && !p.keyless) {
+			continue
 		}
+		if attempts[i] != nil {
+			journal = append(journal, *attempts[i])
+		}
+		if results[i] == nil {
+			missing++
+			continue
+		}
+		readings = append(readings, reading{provider: p.Name(), data: results[i]})
 	}
+
+	return readings, missing, journal
 }
 
-func fetchOpenWeather(data *model.WeatherData, city *model.City, openWeatherAPIKey string, timeNow time.Time) error {
-	escapedCity := url.QueryEscape(city.Name)
-	url := fmt.Sprintf(
-		"https://api.openweathermap.org/data/2.5/weather?q=%s&appid=%s&units=metric",
-		escapedCity, openWeatherAPIKey,
-	)
+// FetchCurrent aggregates current weather for location on demand, nothing is stored.
+// Lookups are public and uncached, so only keyless providers are requested
+// and paid API quota is left for scheduled ingestion.
+func (w *WeatherClient) FetchCurrent(ctx context.Context, city *model.City) (*model.AggregatedWeatherData, error) {
+	if !city.HasCoord() {
+		return nil, fmt.Errorf("location of %s is not resolved", city.Name)
+	}
 
-	var resp *http.Response
+	ctx = logger.With(ctx, "city", city.Name)
 
-	operation := func() error {
-		r, err := http.Get(url)
-		if err != nil {
+	readings, missing, _ := w.fetchCurrent(ctx, city, time.Now(), true)
+	if len(readings) == 0 {
+		return nil, fmt.Errorf("no weather data fetched")
+	}
+
+	return w.aggregator.Aggregate(city, readings, missing), nil
+}
+
+// saveCityWeather stores raw readings and aggregate (if any) in one transaction.
+// Already fetched data is flushed even if ctx is cancelled by shutdown.
+func (w *WeatherClient) saveCityWeather(ctx context.Context, fetched []*model.WeatherData, aggregated *model.AggregatedWeatherData) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+
+	tx, err := w.dbClient.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	if err := w.checkFencing(ctx, &tx); err != nil {
+		return err
+	}
+
+	for _, data := range fetched {
+		if err := w.saveWeatherData(ctx, &tx, data); err != nil {
 			return err
 		}
+	}
 
-		if r.StatusCode == http.StatusTooManyRequests {
-			r.Body.Close()
-			return backoff.Permanent(fmt.Errorf("rate limit exceeded"))
+	if aggregated != nil {
+		if err := w.saveAggregatedWeatherData(ctx, &tx, aggregated); err != nil {
+			return err
 		}
+	}
 
-		if r.StatusCode >= 400 {
-			bodyBytes, _ := io.ReadAll(r.Body)
-			r.Body.Close()
-			return fmt.Errorf("HTTP %d: %s", r.StatusCode, string(bodyBytes))
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	metrics.RowsWritten.WithLabelValues("weather_data").Add(float64(len(fetched)))
+	if aggregated != nil {
+		metrics.RowsWritten.WithLabelValues("aggregated_weather_data").Inc()
+	}
+
+	return nil
+}
+
+// CheckCity makes sure at least one provider knows the city
+func (w *WeatherClient) CheckCity(ctx context.Context, city *model.City) error {
+	if !city.HasCoord() {
+		return fmt.Errorf("location of %s is not resolved", city.Name)
+	}
+
+	var lastErr error = fmt.Errorf("no current weather providers enabled")
+
+	for _, p := range w.providers {
+		if !p.Capabilities().Current || !p.covers(city) {
+			continue
 		}
 
-		resp = r
-		return nil
+		ctx, cancel := context.WithTimeout(ctx, w.callTimeout)
+		ctx, span := tracing.Start(ctx, "provider.CheckCity",
+			attribute.String("weather.provider", p.Name()),
+			attribute.String("weather.city", city.Name),
+		)
+		_, err := p.cb.Execute(func() (interface{}, error) {
+			return p.FetchCurrent(ctx, city, time.Now())
+		})
+		span.SetAttributes(attribute.String("weather.attempt.status", attemptStatus(ctx, err)))
+		tracing.End(span, err)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		lastErr = fmt.Errorf("%s: %w", p.Name(), err)
 	}
 
-	if err := backoff.Retry(operation, backoff.NewExponentialBackOff()); err != nil {
-		return err
+	return lastErr
+}
+
+// ErrRangeNotSupported means no enabled provider forecasts so far ahead
+var ErrRangeNotSupported = errors.New("no enabled provider covers requested range")
+
+// FetchForecast requests forecast from every enabled forecast provider.
+// Result is keyed by provider name, failed providers are skipped.
+func (w *WeatherClient) FetchForecast(ctx context.Context, city *model.City, days int) (map[string][]model.ForecastDay, error) {
+	supports := func(p *providerEntry) bool {
+		capabilities := p.Capabilities()
+		return capabilities.Forecast && days <= capabilities.MaxForecastDays
 	}
-	defer resp.Body.Close()
 
-	var result struct {
-		Main struct {
-			Temp     float64 `json:"temp"`
-			Humidity int     `json:"humidity"`
-		} `json:"main"`
-		Wind struct {
-			Speed float64 `json:"speed"`
-		} `json:"wind"`
-		Weather []struct {
-			Description string `json:"description"`
-		} `json:"weather"`
+	forecasts, err := fetchAll(ctx, w, city, "forecast", supports,
+		func(ctx context.Context, p *providerEntry) ([]model.ForecastDay, error) {
+			return p.FetchForecast(ctx, city, days)
+		},
+		attribute.Int("weather.days", days),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(forecasts) == 0 {
+		return nil, fmt.Errorf("%w: %d days", ErrRangeNotSupported, days)
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return err
+	return forecasts, nil
+}
+
+// FetchHourly requests hourly forecast from every provider able to cover hours.
+// Result is keyed by provider name, failed providers are skipped.
+func (w *WeatherClient) FetchHourly(ctx context.Context, city *model.City, hours int) (map[string][]model.ForecastHour, error) {
+	supports := func(p *providerEntry) bool {
+		_, ok := p.Provider.(HourlyProvider)
+		return ok && hours <= p.Capabilities().MaxForecastHours
 	}
 
-	data.CityID = city.ID
-	data.Source = "OpenWeatherMap"
-	data.Temperature = result.Main.Temp
-	data.Humidity = result.Main.Humidity
-	data.WindSpeed = math.Round(result.Wind.Speed*100) / 100
-	data.CreatedAt = timeNow
+	forecasts, err := fetchAll(ctx, w, city, "hourly", supports,
+		func(ctx context.Context, p *providerEntry) ([]model.ForecastHour, error) {
+			return p.Provider.(HourlyProvider).FetchHourly(ctx, city, hours)
+		},
+		attribute.Int("weather.hours", hours),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(forecasts) == 0 {
+		return nil, fmt.Errorf("%w: %d hours", ErrRangeNotSupported, hours)
+	}
 
-	return nil
+	return forecasts, nil
 }
 
-func fetchWeatherAPI(data *model.WeatherData, city *model.City, weatherAPIKey string, timeNow time.Time) error {
-	escapedCity := url.QueryEscape(city.Name)
-	url := fmt.Sprintf(
-		"https://api.weatherapi.com/v1/current.json?key=%s&q=%s",
-		weatherAPIKey, escapedCity,
+// fetchAll calls fetch of every supporting provider in parallel through its
+// circuit breaker, span of operation "forecast" is "provider.FetchForecast".
+// Error is returned only when no provider succeeded.
+func fetchAll[T any](ctx context.Context, w *WeatherClient, city *model.City, operation string,
+	supports func(p *providerEntry) bool, fetch func(ctx context.Context, p *providerEntry) (T, error),
+	attrs ...attribute.KeyValue) (map[string]T, error) {
+	if !city.HasCoord() {
+		return nil, fmt.Errorf("location of %s is not resolved", city.Name)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		lastErr error
 	)
 
-	var resp *http.Response
+	results := make(map[string]T)
 
-	operation := func() error {
-		r, err := http.Get(url)
-		if err != nil {
-			return err
+	for _, p := range w.providers {
+		if !supports(p) || !p.covers(city) {
+			continue
 		}
 
-		if r.StatusCode == http.StatusTooManyRequests {
-			r.Body.Close()
-			return backoff.Permanent(fmt.Errorf("rate limit exceeded"))
-		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
 
-		if r.StatusCode >= 400 {
-			body, _ := io.ReadAll(r.Body)
-			r.Body.Close()
-			return fmt.Errorf("HTTP %d: %s", r.StatusCode, string(body))
-		}
+			if err := p.acquire(ctx); err != nil {
+				mu.Lock()
+				lastErr = err
+				mu.Unlock()
+				return
+			}
+			defer p.release()
+
+			start := time.Now()
+			ctx, cancel := context.WithTimeout(ctx, w.callTimeout)
+			defer cancel()
+
+			ctx, span := tracing.Start(ctx, "provider.Fetch"+strings.ToUpper(operation[:1])+operation[1:],
+				append([]attribute.KeyValue{
+					attribute.String("weather.provider", p.Name()),
+					attribute.String("weather.city", city.Name),
+				}, attrs...)...,
+			)
+			ctx = logger.With(logger.With(ctx, "city", city.Name), "provider", p.Name())
+			res, err := p.cb.Execute(func() (interface{}, error) {
+				return fetch(ctx, p)
+			})
+			status := attemptStatus(ctx, err)
+			metrics.ObserveProviderRequest(p.Name(), operation, status, time.Since(start))
+			span.SetAttributes(
+				attribute.String("weather.attempt.status", status),
+				attribute.String("weather.circuit_state", p.cb.State().String()),
+			)
+			tracing.End(span, err)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				logger.Ctx(ctx).Error().Err(err).
+					Str("status", status).
+					Str("circuit_state", p.cb.State().String()).
+					Msg("provider." + operation + "_failed")
+				lastErr = err
+				return
+			}
 
-		resp = r
-		return nil
+			results[p.Name()] = res.(T)
+		}()
 	}
 
-	if err := backoff.Retry(operation, backoff.NewExponentialBackOff()); err != nil {
-		return err
+	wg.Wait()
+
+	if len(results) == 0 && lastErr != nil {
+		return nil, lastErr
 	}
 
-	defer resp.Body.Close()
+	return results, nil
+}
 
-	// JSON → структура
-	var result struct {
-		Current struct {
-			TempC     float64 `json:"temp_c"`
-			Humidity  int     `json:"humidity"`
-			WindKph   float64 `json:"wind_kph"`
-			Condition struct {
-				Text string `json:"text"`
-			} `json:"condition"`
-		} `json:"current"`
+// checkFencing rejects writes of instance which lost leadership
+// after newer leader has already written with greater token
+func (w *WeatherClient) checkFencing(ctx context.Context, tx *bun.Tx) error {
+	if w.leadership == nil {
+		return nil
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return err
+	token := w.leadership.Token()
+	if token == 0 {
+		tx.Rollback()
+		return fmt.Errorf("check fencing: instance is not leader")
 	}
 
-	data.CityID = city.ID
-	data.Source = "WeatherAPI"
-	data.Temperature = result.Current.TempC
-	data.Humidity = result.Current.Humidity
-	data.WindSpeed = math.Round((result.Current.WindKph/3.6)*100) / 100
-	data.CreatedAt = timeNow
-
+	res, err := tx.NewUpdate().Table("ingestion_fencing").
+		Set("token = ?", token).
+		Set("updated_at = NOW()").
+		Where("id = 1").
+		Where("token <= ?", token).
+		Exec(ctx)
+	if err == nil {
+		if n, _ := res.RowsAffected(); n == 0 {
+			err = fmt.Errorf("stale fencing token %d", token)
+		}
+	}
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("check fencing: %w", err)
+	}
 	return nil
 }
 
-func (w *WeatherClient) saveWeatherData(tx *bun.Tx, data *model.WeatherData) {
-	_, err := tx.NewInsert().Model(data).Exec(context.Background())
+func (w *WeatherClient) saveWeatherData(ctx context.Context, tx *bun.Tx, data *model.WeatherData) error {
+	_, err := tx.NewInsert().Model(data).Exec(ctx)
 	if err != nil {
 		tx.Rollback()
-		log.Errorf("Failed to save data: %v", err)
+		return fmt.Errorf("save weather data: %w", err)
 	}
+	return nil
 }
 
-func (w *WeatherClient) saveAggregatedWeatherData(tx *bun.Tx, data *model.AggregatedWeatherData) {
-	_, err := tx.NewInsert().Model(data).Exec(context.Background())
+func (w *WeatherClient) saveAggregatedWeatherData(ctx context.Context, tx *bun.Tx, data *model.AggregatedWeatherData) error {
+	_, err := tx.NewInsert().Model(data).Exec(ctx)
 	if err != nil {
 		tx.Rollback()
-		log.Errorf("Failed to save data: %v", err)
+		return fmt.Errorf("save aggregated weather data: %w", err)
 	}
+	return nil
 }
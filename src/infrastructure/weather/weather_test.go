@@ -0,0 +1,54 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"weather-data-aggregator-service/src/domain/model"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestFetchCurrentAttemptErrorHasNoKey(t *testing.T) {
+	const key = "secret-api-key"
+
+	// Transport errors are *url.Error quoting request URL
+	client := &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})}
+
+	w := &WeatherClient{
+		callTimeout: 5 * time.Second,
+		providers: []*providerEntry{
+			{Provider: &openWeatherProvider{client: client, apiKey: key}, cb: newCircuitBreaker("openweather")},
+			{Provider: &weatherAPIProvider{client: client, apiKey: key}, cb: newCircuitBreaker("weatherapi")},
+		},
+	}
+
+	lat, lon := 52.52, 13.405
+	city := &model.City{Name: "Berlin", Latitude: &lat, Longitude: &lon}
+
+	readings, missing, attempts := w.fetchCurrent(context.Background(), city, time.Now(), false)
+	if len(readings) != 0 || missing != 2 {
+		t.Fatalf("readings = %d, missing = %d, want 0 and 2", len(readings), missing)
+	}
+	if len(attempts) != 2 {
+		t.Fatalf("attempts = %d, want 2", len(attempts))
+	}
+
+	for _, a := range attempts {
+		if a.Error == "" {
+			t.Errorf("%s attempt has no error", a.Provider)
+		}
+		if strings.Contains(a.Error, key) {
+			t.Errorf("%s attempt error contains API key: %s", a.Provider, a.Error)
+		}
+	}
+}
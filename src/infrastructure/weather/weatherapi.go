@@ -0,0 +1,110 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/viper"
+	"math"
+	"net/http"
+	"net/url"
+	"time"
+	"weather-data-aggregator-service/src/domain/model"
+)
+
+func init() {
+	RegisterProvider("weatherapi", true, newWeatherAPIProvider, LegacyKey("weather_api.key"))
+}
+
+type weatherAPIProvider struct {
+	client *http.Client
+	apiKey string
+}
+
+func newWeatherAPIProvider(cfg *viper.Viper, client *http.Client, _ StateStore) (Provider, error) {
+	key := cfg.GetString("key")
+	if key == "" {
+		return nil, fmt.Errorf("key is required")
+	}
+
+	return &weatherAPIProvider{client: client, apiKey: key}, nil
+}
+
+func (p *weatherAPIProvider) Name() string {
+	return "weatherapi"
+}
+
+func (p *weatherAPIProvider) Capabilities() Capabilities {
+	return Capabilities{
+		Current:         true,
+		Forecast:        true,
+		MaxForecastDays: 7,
+	}
+}
+
+func (p *weatherAPIProvider) FetchCurrent(ctx context.Context, city *model.City, timeNow time.Time) (*model.WeatherData, error) {
+	url := fmt.Sprintf(
+		"https://api.weatherapi.com/v1/current.json?key=%s&q=%s",
+		p.apiKey, coordQuery(city),
+	)
+
+	// JSON → структура
+	var result struct {
+		Current struct {
+			TempC     float64 `json:"temp_c"`
+			Humidity  int     `json:"humidity"`
+			WindKph   float64 `json:"wind_kph"`
+			Condition struct {
+				Text string `json:"text"`
+			} `json:"condition"`
+		} `json:"current"`
+	}
+
+	if err := getJSON(ctx, p.client, url, &result); err != nil {
+		return nil, err
+	}
+
+	return &model.WeatherData{
+		CityID:      city.ID,
+		Source:      "WeatherAPI",
+		Temperature: result.Current.TempC,
+		Humidity:    result.Current.Humidity,
+		WindSpeed:   math.Round((result.Current.WindKph/3.6)*100) / 100,
+		CreatedAt:   timeNow,
+	}, nil
+}
+
+func (p *weatherAPIProvider) FetchForecast(ctx context.Context, city *model.City, days int) ([]model.ForecastDay, error) {
+	if days < 1 || days > 7 {
+		return nil, fmt.Errorf("days must be between 1 and 7")
+	}
+
+	url := fmt.Sprintf(
+		"https://api.weatherapi.com/v1/forecast.json?key=%s&q=%s&days=%d",
+		p.apiKey, coordQuery(city), days,
+	)
+
+	var apiResp model.OneCallRespWA
+	if err := getJSON(ctx, p.client, url, &apiResp); err != nil {
+		return nil, err
+	}
+
+	result := make([]model.ForecastDay, len(apiResp.Forecast.Forecastday))
+	for i, d := range apiResp.Forecast.Forecastday {
+		date, _ := time.Parse("2006-01-02", d.Date)
+		result[i] = model.ForecastDay{
+			Date:        date,
+			Temperature: d.Day.AvgtempC,
+			Humidity:    int(d.Day.Avghumidity),
+			WindSpeed:   d.Day.MaxwindKph / 3.6, // конвертируем km/h в m/s
+			Description: d.Day.Condition.Text,
+		}
+	}
+
+	return result, nil
+}
+
+// coordQuery formats city location as WeatherAPI "lat,lon" query
+func coordQuery(city *model.City) string {
+	coord := city.Coord()
+	return url.QueryEscape(fmt.Sprintf("%f,%f", coord.Lat, coord.Lon))
+}
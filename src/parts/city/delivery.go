@@ -0,0 +1,13 @@
+package city
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// Controller represent controllers
+type Controller interface {
+	List(c *fiber.Ctx) error
+	Create(c *fiber.Ctx) error
+	Update(c *fiber.Ctx) error
+	Delete(c *fiber.Ctx) error
+}
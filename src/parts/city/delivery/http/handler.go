@@ -0,0 +1,121 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/parts/city"
+)
+
+type cityController struct {
+	useCase city.UseCase
+}
+
+func NewCityController(useCase city.UseCase) city.Controller {
+	return &cityController{useCase}
+}
+
+// List returns all cities including disabled ones
+func (u *cityController) List(c *fiber.Ctx) error {
+	result, err := u.useCase.List(c.UserContext())
+	if err != nil {
+		return fmt.Errorf("failed to list cities: %w", err)
+	}
+
+	return c.JSON(result)
+}
+
+// Create adds city after checking weather providers know it
+func (u *cityController) Create(c *fiber.Ctx) error {
+	var req model.CityRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "name is required")
+	}
+
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		return fiber.NewError(fiber.StatusBadRequest, "latitude and longitude must be set together")
+	}
+
+	if req.Latitude != nil && (*req.Latitude < -90 || *req.Latitude > 90 || *req.Longitude < -180 || *req.Longitude > 180) {
+		return fiber.NewError(fiber.StatusBadRequest, "latitude or longitude is out of range")
+	}
+
+	if req.Country != "" && len(req.Country) != 2 {
+		return fiber.NewError(fiber.StatusBadRequest, "country must be ISO 3166-1 alpha-2 code")
+	}
+
+	if req.Timezone != "" {
+		if _, err := time.LoadLocation(req.Timezone); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "timezone must be IANA timezone name")
+		}
+	}
+
+	result, err := u.useCase.Create(c.UserContext(), req)
+	if err != nil {
+		return cityError(err, "failed to create city")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+// Update enables or disables city
+func (u *cityController) Update(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid city id")
+	}
+
+	var req model.CityRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if req.Enabled == nil {
+		return fiber.NewError(fiber.StatusBadRequest, "enabled is required")
+	}
+
+	result, err := u.useCase.Update(c.UserContext(), id, req)
+	if err != nil {
+		return cityError(err, "failed to update city")
+	}
+
+	return c.JSON(result)
+}
+
+// Delete removes city with all its weather data
+func (u *cityController) Delete(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid city id")
+	}
+
+	if err := u.useCase.Delete(c.UserContext(), id); err != nil {
+		return cityError(err, "failed to delete city")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// cityError maps domain errors to HTTP status codes
+func cityError(err error, msg string) error {
+	switch {
+	case errors.Is(err, city.ErrNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, city.ErrAlreadyExists):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	case errors.Is(err, city.ErrUnknownCity):
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+}
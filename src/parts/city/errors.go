@@ -0,0 +1,9 @@
+package city
+
+import "errors"
+
+var (
+	ErrNotFound      = errors.New("city not found")
+	ErrAlreadyExists = errors.New("city already exists")
+	ErrUnknownCity   = errors.New("city is unknown to weather providers")
+)
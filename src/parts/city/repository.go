@@ -0,0 +1,24 @@
+package city
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"weather-data-aggregator-service/src/domain/model"
+)
+
+// PostgresRepository represent repository contract
+type PostgresRepository interface {
+	List(ctx context.Context) ([]model.City, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*model.City, error)
+	GetByName(ctx context.Context, name, country string) (*model.City, error)
+	Create(ctx context.Context, city *model.City) error
+	Update(ctx context.Context, city *model.City) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// WeatherClient represent ingestion client contract used by city management
+type WeatherClient interface {
+	Geocode(ctx context.Context, name, country string) (*model.City, error)
+	CheckCity(ctx context.Context, city *model.City) error
+	ReloadCities(ctx context.Context) error
+}
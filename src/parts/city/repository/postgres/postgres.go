@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
+	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/parts/city"
+)
+
+type cityPostgresRepository struct {
+	db *bun.DB
+}
+
+func NewCityPostgresRepository(db *bun.DB) city.PostgresRepository {
+	return &cityPostgresRepository{db}
+}
+
+func (r *cityPostgresRepository) List(ctx context.Context) ([]model.City, error) {
+	cities := make([]model.City, 0)
+	err := r.db.NewSelect().Model(&cities).Order("name ASC").Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cities, nil
+}
+
+func (r *cityPostgresRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.City, error) {
+	var c model.City
+	err := r.db.NewSelect().Model(&c).Where("id = ?", id).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, city.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *cityPostgresRepository) GetByName(ctx context.Context, name, country string) (*model.City, error) {
+	var c model.City
+	err := r.db.NewSelect().Model(&c).
+		Where("lower(name) = lower(?)", name).
+		Where("country = ?", country).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, city.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *cityPostgresRepository) Create(ctx context.Context, c *model.City) error {
+	_, err := r.db.NewInsert().Model(c).Returning("*").Exec(ctx)
+
+	// Same city created concurrently
+	var pgErr pgdriver.Error
+	if errors.As(err, &pgErr) && pgErr.Field('C') == "23505" {
+		return city.ErrAlreadyExists
+	}
+	return err
+}
+
+func (r *cityPostgresRepository) Update(ctx context.Context, c *model.City) error {
+	res, err := r.db.NewUpdate().Model(c).Column("enabled").WherePK().Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return city.ErrNotFound
+	}
+	return nil
+}
+
+func (r *cityPostgresRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.NewDelete().Model((*model.City)(nil)).Where("id = ?", id).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return city.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package city
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"weather-data-aggregator-service/src/domain/model"
+)
+
+// UseCase represent usecases
+type UseCase interface {
+	List(ctx context.Context) ([]model.City, error)
+	Create(ctx context.Context, req model.CityRequest) (*model.City, error)
+	Update(ctx context.Context, id uuid.UUID, req model.CityRequest) (*model.City, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
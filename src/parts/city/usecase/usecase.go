@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"strings"
+	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/infrastructure/logger"
+	"weather-data-aggregator-service/src/infrastructure/tracing"
+	"weather-data-aggregator-service/src/parts/city"
+)
+
+type cityUseCase struct {
+	pRepo         city.PostgresRepository
+	weatherClient city.WeatherClient
+}
+
+func NewCityUseCase(pRepo city.PostgresRepository, weatherClient city.WeatherClient) city.UseCase {
+	return &cityUseCase{pRepo, weatherClient}
+}
+
+func (u *cityUseCase) List(ctx context.Context) (res []model.City, err error) {
+	ctx, span := tracing.Start(ctx, "city.List")
+	defer func() { tracing.End(span, err) }()
+
+	return u.pRepo.List(ctx)
+}
+
+func (u *cityUseCase) Create(ctx context.Context, req model.CityRequest) (res *model.City, err error) {
+	ctx, span := tracing.Start(ctx, "city.Create")
+	defer func() { tracing.End(span, err) }()
+
+	c := &model.City{
+		Name:    strings.TrimSpace(req.Name),
+		Enabled: true,
+	}
+	if req.Enabled != nil {
+		c.Enabled = *req.Enabled
+	}
+
+	if err := u.resolveLocation(ctx, c, req); err != nil {
+		return nil, fmt.Errorf("%w: %v", city.ErrUnknownCity, err)
+	}
+
+	// Country is known only after geocoding
+	_, err = u.pRepo.GetByName(ctx, c.Name, c.Country)
+	if err == nil {
+		return nil, city.ErrAlreadyExists
+	}
+	if !errors.Is(err, city.ErrNotFound) {
+		return nil, err
+	}
+
+	if err := u.weatherClient.CheckCity(ctx, c); err != nil {
+		return nil, fmt.Errorf("%w: %v", city.ErrUnknownCity, err)
+	}
+
+	if err := u.pRepo.Create(ctx, c); err != nil {
+		return nil, err
+	}
+
+	u.reload(ctx)
+	return c, nil
+}
+
+func (u *cityUseCase) Update(ctx context.Context, id uuid.UUID, req model.CityRequest) (res *model.City, err error) {
+	ctx, span := tracing.Start(ctx, "city.Update")
+	defer func() { tracing.End(span, err) }()
+
+	c, err := u.pRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Enabled != nil {
+		c.Enabled = *req.Enabled
+	}
+
+	if err := u.pRepo.Update(ctx, c); err != nil {
+		return nil, err
+	}
+
+	u.reload(ctx)
+	return c, nil
+}
+
+func (u *cityUseCase) Delete(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := tracing.Start(ctx, "city.Delete")
+	defer func() { tracing.End(span, err) }()
+
+	if err := u.pRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	u.reload(ctx)
+	return nil
+}
+
+// resolveLocation takes location from request or geocodes city by name
+func (u *cityUseCase) resolveLocation(ctx context.Context, c *model.City, req model.CityRequest) error {
+	if req.Latitude != nil && req.Longitude != nil {
+		c.Latitude = req.Latitude
+		c.Longitude = req.Longitude
+		c.Country = strings.ToUpper(req.Country)
+		c.Timezone = req.Timezone
+		return nil
+	}
+
+	resolved, err := u.weatherClient.Geocode(ctx, c.Name, req.Country)
+	if err != nil {
+		return err
+	}
+
+	c.Latitude = resolved.Latitude
+	c.Longitude = resolved.Longitude
+	c.Country = resolved.Country
+	c.Timezone = resolved.Timezone
+	return nil
+}
+
+// reload makes weather client pick up changed city list
+func (u *cityUseCase) reload(ctx context.Context) {
+	if err := u.weatherClient.ReloadCities(ctx); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("cities.reload_failed")
+	}
+}
@@ -0,0 +1,10 @@
+package cluster
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// Controller represent controllers
+type Controller interface {
+	GetLeader(c *fiber.Ctx) error
+}
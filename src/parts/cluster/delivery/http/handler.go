@@ -0,0 +1,25 @@
+package http
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"weather-data-aggregator-service/src/parts/cluster"
+)
+
+type clusterController struct {
+	useCase cluster.UseCase
+}
+
+func NewClusterController(useCase cluster.UseCase) cluster.Controller {
+	return &clusterController{useCase}
+}
+
+// GetLeader returns instance which runs scheduled ingestion
+func (u *clusterController) GetLeader(c *fiber.Ctx) error {
+	result, err := u.useCase.GetLeader(c.UserContext())
+	if err != nil {
+		return fmt.Errorf("failed to get leader: %w", err)
+	}
+
+	return c.JSON(result)
+}
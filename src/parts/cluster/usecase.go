@@ -0,0 +1,16 @@
+package cluster
+
+import (
+	"context"
+	"weather-data-aggregator-service/src/domain/model"
+)
+
+// UseCase represent usecases
+type UseCase interface {
+	GetLeader(ctx context.Context) (*model.LeaderStatus, error)
+}
+
+// Elector represent leader election contract
+type Elector interface {
+	Status(ctx context.Context) (*model.LeaderStatus, error)
+}
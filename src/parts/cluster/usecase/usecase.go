@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"context"
+	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/infrastructure/tracing"
+	"weather-data-aggregator-service/src/parts/cluster"
+)
+
+type clusterUseCase struct {
+	elector cluster.Elector
+}
+
+func NewClusterUseCase(elector cluster.Elector) cluster.UseCase {
+	return &clusterUseCase{elector}
+}
+
+func (u *clusterUseCase) GetLeader(ctx context.Context) (res *model.LeaderStatus, err error) {
+	ctx, span := tracing.Start(ctx, "cluster.GetLeader")
+	defer func() { tracing.End(span, err) }()
+
+	return u.elector.Status(ctx)
+}
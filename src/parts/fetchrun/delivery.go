@@ -0,0 +1,11 @@
+package fetchrun
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// Controller represent controllers
+type Controller interface {
+	List(c *fiber.Ctx) error
+	Get(c *fiber.Ctx) error
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/parts/fetchrun"
+)
+
+type fetchRunController struct {
+	useCase fetchrun.UseCase
+}
+
+func NewFetchRunController(useCase fetchrun.UseCase) fetchrun.Controller {
+	return &fetchRunController{useCase}
+}
+
+// List returns latest scheduled fetch runs
+func (u *fetchRunController) List(c *fiber.Ctx) error {
+	var q model.FetchRunsQuery
+
+	if err := c.QueryParser(&q); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid query parameters")
+	}
+
+	if q.Limit == 0 {
+		q.Limit = 20
+	}
+	if q.Limit < 1 || q.Limit > 100 {
+		return fiber.NewError(fiber.StatusBadRequest, "limit must be between 1 and 100")
+	}
+
+	result, err := u.useCase.List(c.UserContext(), q)
+	if err != nil {
+		return fmt.Errorf("failed to list fetch runs: %w", err)
+	}
+
+	return c.JSON(result)
+}
+
+// Get returns fetch run with attempts of every provider
+func (u *fetchRunController) Get(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid fetch run id")
+	}
+
+	result, err := u.useCase.Get(c.UserContext(), id)
+	if errors.Is(err, fetchrun.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get fetch run: %w", err)
+	}
+
+	return c.JSON(result)
+}
@@ -0,0 +1,5 @@
+package fetchrun
+
+import "errors"
+
+var ErrNotFound = errors.New("fetch run not found")
@@ -0,0 +1,13 @@
+package fetchrun
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"weather-data-aggregator-service/src/domain/model"
+)
+
+// PostgresRepository represent repository contract
+type PostgresRepository interface {
+	List(ctx context.Context, limit int) ([]model.FetchRun, error)
+	Get(ctx context.Context, id uuid.UUID) (*model.FetchRun, error)
+}
@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/parts/fetchrun"
+)
+
+type fetchRunPostgresRepository struct {
+	db *bun.DB
+}
+
+func NewFetchRunPostgresRepository(db *bun.DB) fetchrun.PostgresRepository {
+	return &fetchRunPostgresRepository{db}
+}
+
+// List returns latest runs without attempts
+func (r *fetchRunPostgresRepository) List(ctx context.Context, limit int) ([]model.FetchRun, error) {
+	runs := make([]model.FetchRun, 0)
+	err := r.db.NewSelect().Model(&runs).Order("started_at DESC").Limit(limit).Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// Get returns run with attempts of every city and provider
+func (r *fetchRunPostgresRepository) Get(ctx context.Context, id uuid.UUID) (*model.FetchRun, error) {
+	var run model.FetchRun
+	err := r.db.NewSelect().Model(&run).
+		Relation("Attempts", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("fa.created_at ASC")
+		}).
+		Relation("Attempts.City").
+		Where("fr.id = ?", id).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fetchrun.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
@@ -0,0 +1,13 @@
+package fetchrun
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"weather-data-aggregator-service/src/domain/model"
+)
+
+// UseCase represent usecases
+type UseCase interface {
+	List(ctx context.Context, q model.FetchRunsQuery) ([]model.FetchRun, error)
+	Get(ctx context.Context, id uuid.UUID) (*model.FetchRun, error)
+}
@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/infrastructure/tracing"
+	"weather-data-aggregator-service/src/parts/fetchrun"
+)
+
+type fetchRunUseCase struct {
+	pRepo fetchrun.PostgresRepository
+}
+
+func NewFetchRunUseCase(pRepo fetchrun.PostgresRepository) fetchrun.UseCase {
+	return &fetchRunUseCase{pRepo}
+}
+
+func (u *fetchRunUseCase) List(ctx context.Context, q model.FetchRunsQuery) (res []model.FetchRun, err error) {
+	ctx, span := tracing.Start(ctx, "fetchrun.List")
+	defer func() { tracing.End(span, err) }()
+
+	return u.pRepo.List(ctx, q.Limit)
+}
+
+func (u *fetchRunUseCase) Get(ctx context.Context, id uuid.UUID) (res *model.FetchRun, err error) {
+	ctx, span := tracing.Start(ctx, "fetchrun.Get")
+	defer func() { tracing.End(span, err) }()
+
+	return u.pRepo.Get(ctx, id)
+}
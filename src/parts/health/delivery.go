@@ -0,0 +1,11 @@
+package health
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// Controller represent controllers
+type Controller interface {
+	Live(c *fiber.Ctx) error
+	Ready(c *fiber.Ctx) error
+}
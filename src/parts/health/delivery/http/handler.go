@@ -0,0 +1,34 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/parts/health"
+)
+
+type healthController struct {
+	useCase health.UseCase
+}
+
+func NewHealthController(useCase health.UseCase) health.Controller {
+	return &healthController{useCase}
+}
+
+// Live is liveness probe
+func (u *healthController) Live(c *fiber.Ctx) error {
+	return report(c, u.useCase.Live(c.UserContext()))
+}
+
+// Ready is readiness probe, degraded service is still ready
+func (u *healthController) Ready(c *fiber.Ctx) error {
+	return report(c, u.useCase.Ready(c.UserContext()))
+}
+
+func report(c *fiber.Ctx, r *model.HealthReport) error {
+	status := fiber.StatusOK
+	if r.Status == model.HealthDown {
+		status = fiber.StatusServiceUnavailable
+	}
+
+	return c.Status(status).JSON(r)
+}
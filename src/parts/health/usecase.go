@@ -0,0 +1,18 @@
+package health
+
+import (
+	"context"
+	"weather-data-aggregator-service/src/domain/model"
+)
+
+// UseCase represent usecases
+type UseCase interface {
+	Live(ctx context.Context) *model.HealthReport
+	Ready(ctx context.Context) *model.HealthReport
+}
+
+// Checker represent dependency check contract
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) model.HealthCheck
+}
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"github.com/spf13/viper"
+	"sync"
+	"time"
+	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/parts/health"
+)
+
+var severity = map[string]int{
+	model.HealthUp:       0,
+	model.HealthDegraded: 1,
+	model.HealthDown:     2,
+}
+
+type healthUseCase struct {
+	checkers []health.Checker
+	timeout  time.Duration
+}
+
+func NewHealthUseCase(checkers ...health.Checker) health.UseCase {
+	viper.SetDefault("health.check_timeout", 2*time.Second)
+
+	return &healthUseCase{
+		checkers: checkers,
+		timeout:  viper.GetDuration("health.check_timeout"),
+	}
+}
+
+// Live reports process is running, dependencies are not checked
+// so their outage does not restart every instance
+func (u *healthUseCase) Live(context.Context) *model.HealthReport {
+	return &model.HealthReport{Status: model.HealthUp, CheckedAt: time.Now()}
+}
+
+// Ready runs all checkers in parallel, each is limited by health.check_timeout
+func (u *healthUseCase) Ready(ctx context.Context) *model.HealthReport {
+	ctx, cancel := context.WithTimeout(ctx, u.timeout)
+	defer cancel()
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+
+	report := &model.HealthReport{
+		Status:    model.HealthUp,
+		Checks:    make(map[string]model.HealthCheck, len(u.checkers)),
+		CheckedAt: time.Now(),
+	}
+
+	for _, c := range u.checkers {
+		wg.Add(1)
+		go func(c health.Checker) {
+			defer wg.Done()
+
+			start := time.Now()
+			res := c.Check(ctx)
+			res.LatencyMs = time.Since(start).Milliseconds()
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Checks[c.Name()] = res
+			if severity[res.Status] > severity[report.Status] {
+				report.Status = res.Status
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	return report
+}
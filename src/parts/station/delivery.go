@@ -0,0 +1,14 @@
+package station
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// Controller represent controllers
+type Controller interface {
+	PushWU(c *fiber.Ctx) error
+	PushEcowitt(c *fiber.Ctx) error
+	List(c *fiber.Ctx) error
+	Create(c *fiber.Ctx) error
+	Delete(c *fiber.Ctx) error
+}
@@ -0,0 +1,187 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"strconv"
+	"strings"
+	"time"
+	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/parts/station"
+)
+
+// dateUTCLayout is "dateutc" format of both protocols
+const dateUTCLayout = "2006-01-02 15:04:05"
+
+type stationController struct {
+	useCase station.UseCase
+}
+
+func NewStationController(useCase station.UseCase) station.Controller {
+	return &stationController{useCase}
+}
+
+// PushWU accepts Weather Underground updateweatherstation.php query,
+// station is sent as ID and its key as PASSWORD
+func (u *stationController) PushWU(c *fiber.Ctx) error {
+	p := model.StationPush{
+		StationID: c.Query("ID"),
+		Key:       c.Query("PASSWORD"),
+		Protocol:  model.ProtocolWU,
+	}
+
+	err := parsePush(&p, c.Query, map[string]**float64{
+		"tempf":          &p.TempF,
+		"humidity":       &p.Humidity,
+		"dewptf":         &p.DewPointF,
+		"windspeedmph":   &p.WindSpeedMph,
+		"windgustmph":    &p.WindGustMph,
+		"winddir":        &p.WindDir,
+		"baromin":        &p.BaromIn,
+		"rainin":         &p.RainRateIn,
+		"dailyrainin":    &p.DailyRainIn,
+		"solarradiation": &p.SolarRadiation,
+		"UV":             &p.UV,
+	})
+	if err != nil {
+		return err
+	}
+
+	return u.push(c, p)
+}
+
+// PushEcowitt accepts Ecowitt custom server form POST. Station is PASSKEY
+// (hash of its MAC address), protocol has no secret, so key is last segment
+// of path configured in station.
+func (u *stationController) PushEcowitt(c *fiber.Ctx) error {
+	p := model.StationPush{
+		StationID: c.FormValue("PASSKEY"),
+		Key:       c.Params("key"),
+		Protocol:  model.ProtocolEcowitt,
+	}
+
+	err := parsePush(&p, c.FormValue, map[string]**float64{
+		"tempf":          &p.TempF,
+		"humidity":       &p.Humidity,
+		"windspeedmph":   &p.WindSpeedMph,
+		"windgustmph":    &p.WindGustMph,
+		"winddir":        &p.WindDir,
+		"baromrelin":     &p.BaromIn,
+		"rainratein":     &p.RainRateIn,
+		"dailyrainin":    &p.DailyRainIn,
+		"solarradiation": &p.SolarRadiation,
+		"uv":             &p.UV,
+	})
+	if err != nil {
+		return err
+	}
+
+	return u.push(c, p)
+}
+
+func (u *stationController) push(c *fiber.Ctx, p model.StationPush) error {
+	if err := u.useCase.Push(c.UserContext(), p); err != nil {
+		return stationError(err, "failed to store station reading")
+	}
+
+	// Weather Underground clients expect this body
+	return c.SendString("success")
+}
+
+// List returns registered stations, keys are not included
+func (u *stationController) List(c *fiber.Ctx) error {
+	result, err := u.useCase.List(c.UserContext())
+	if err != nil {
+		return fmt.Errorf("failed to list stations: %w", err)
+	}
+
+	return c.JSON(result)
+}
+
+// Create registers station allowed to push readings of city
+func (u *stationController) Create(c *fiber.Ctx) error {
+	var req model.StationRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if strings.TrimSpace(req.StationID) == "" || req.Key == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "station_id and key are required")
+	}
+
+	if req.CityID == uuid.Nil {
+		return fiber.NewError(fiber.StatusBadRequest, "city_id is required")
+	}
+
+	result, err := u.useCase.Create(c.UserContext(), req)
+	if err != nil {
+		return stationError(err, "failed to create station")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+// Delete removes station, its stored readings are kept
+func (u *stationController) Delete(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid station id")
+	}
+
+	if err := u.useCase.Delete(c.UserContext(), id); err != nil {
+		return stationError(err, "failed to delete station")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// stationError maps domain errors to HTTP status codes
+func stationError(err error, msg string) error {
+	switch {
+	case errors.Is(err, station.ErrUnauthorized):
+		return fiber.NewError(fiber.StatusUnauthorized, err.Error())
+	case errors.Is(err, station.ErrIncomplete):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, station.ErrNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, station.ErrAlreadyExists):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	case errors.Is(err, station.ErrUnknownCity):
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+}
+
+// parsePush reads dateutc and numeric fields, empty values and
+// -9999 (sensor not connected) are left nil
+func parsePush(p *model.StationPush, value func(key string, defaultValue ...string) string, fields map[string]**float64) error {
+	if date := value("dateutc"); date != "" && date != "now" {
+		t, err := time.Parse(dateUTCLayout, date)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "dateutc must be \"now\" or YYYY-MM-DD HH:MM:SS")
+		}
+		p.Time = t
+	}
+
+	for key, dst := range fields {
+		raw := value(key)
+		if raw == "" {
+			continue
+		}
+
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, key+" must be a number")
+		}
+		if v == -9999 {
+			continue
+		}
+		*dst = &v
+	}
+
+	return nil
+}
@@ -0,0 +1,11 @@
+package station
+
+import "errors"
+
+var (
+	ErrUnauthorized  = errors.New("invalid station ID or key")
+	ErrIncomplete    = errors.New("temperature, humidity and wind speed are required")
+	ErrNotFound      = errors.New("station not found")
+	ErrAlreadyExists = errors.New("station already exists")
+	ErrUnknownCity   = errors.New("station city is not tracked")
+)
@@ -0,0 +1,20 @@
+package station
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"time"
+	"weather-data-aggregator-service/src/domain/model"
+)
+
+// PostgresRepository represent repository contract
+type PostgresRepository interface {
+	List(ctx context.Context) ([]model.Station, error)
+	GetByStationID(ctx context.Context, stationID string) (*model.Station, error)
+	Create(ctx context.Context, s *model.Station) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	CityExists(ctx context.Context, cityID uuid.UUID) (bool, error)
+	// LatestAt returns time of latest stored reading of source, zero if none
+	LatestAt(ctx context.Context, cityID uuid.UUID, source string) (time.Time, error)
+	Save(ctx context.Context, data *model.WeatherData) error
+}
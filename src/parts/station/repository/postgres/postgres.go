@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"time"
+	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/infrastructure/metrics"
+	"weather-data-aggregator-service/src/parts/station"
+)
+
+type stationPostgresRepository struct {
+	db *bun.DB
+}
+
+func NewStationPostgresRepository(db *bun.DB) station.PostgresRepository {
+	return &stationPostgresRepository{db}
+}
+
+func (r *stationPostgresRepository) List(ctx context.Context) ([]model.Station, error) {
+	stations := make([]model.Station, 0)
+	err := r.db.NewSelect().Model(&stations).Order("station_id ASC").Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return stations, nil
+}
+
+func (r *stationPostgresRepository) GetByStationID(ctx context.Context, stationID string) (*model.Station, error) {
+	var s model.Station
+	err := r.db.NewSelect().Model(&s).Where("station_id = ?", stationID).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, station.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *stationPostgresRepository) Create(ctx context.Context, s *model.Station) error {
+	_, err := r.db.NewInsert().Model(s).Returning("*").Exec(ctx)
+	return err
+}
+
+func (r *stationPostgresRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.NewDelete().Model((*model.Station)(nil)).Where("id = ?", id).Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return station.ErrNotFound
+	}
+	return nil
+}
+
+func (r *stationPostgresRepository) CityExists(ctx context.Context, cityID uuid.UUID) (bool, error) {
+	return r.db.NewSelect().Model((*model.City)(nil)).Where("id = ?", cityID).Exists(ctx)
+}
+
+func (r *stationPostgresRepository) LatestAt(ctx context.Context, cityID uuid.UUID, source string) (time.Time, error) {
+	var latest time.Time
+	err := r.db.NewSelect().Model((*model.WeatherData)(nil)).
+		Column("created_at").
+		Where("city_id = ?", cityID).
+		Where("source = ?", source).
+		OrderExpr("created_at DESC").
+		Limit(1).
+		Scan(ctx, &latest)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	return latest, err
+}
+
+func (r *stationPostgresRepository) Save(ctx context.Context, data *model.WeatherData) error {
+	if _, err := r.db.NewInsert().Model(data).Exec(ctx); err != nil {
+		return fmt.Errorf("save station reading: %w", err)
+	}
+
+	metrics.RowsWritten.WithLabelValues("weather_data").Inc()
+	return nil
+}
@@ -0,0 +1,15 @@
+package station
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"weather-data-aggregator-service/src/domain/model"
+)
+
+// UseCase represent usecases
+type UseCase interface {
+	Push(ctx context.Context, p model.StationPush) error
+	List(ctx context.Context) ([]model.Station, error)
+	Create(ctx context.Context, req model.StationRequest) (*model.Station, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
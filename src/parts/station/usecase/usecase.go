@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/attribute"
+	"math"
+	"strings"
+	"time"
+	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/infrastructure/logger"
+	"weather-data-aggregator-service/src/infrastructure/tracing"
+	"weather-data-aggregator-service/src/parts/station"
+)
+
+type stationUseCase struct {
+	pRepo station.PostgresRepository
+}
+
+func NewStationUseCase(pRepo station.PostgresRepository) station.UseCase {
+	return &stationUseCase{pRepo}
+}
+
+// Push stores reading of authenticated station as data source of station city.
+// Readings more frequent than pws.min_interval are dropped.
+func (u *stationUseCase) Push(ctx context.Context, p model.StationPush) (err error) {
+	viper.SetDefault("pws.min_interval", time.Minute)
+
+	ctx, span := tracing.Start(ctx, "station.Push", attribute.String("pws.protocol", p.Protocol))
+	defer func() { tracing.End(span, err) }()
+
+	s, err := u.authenticate(ctx, p.StationID, p.Key)
+	if err != nil {
+		return err
+	}
+
+	span.SetAttributes(attribute.String("pws.station", s.StationID))
+	ctx = logger.With(ctx, "station", s.StationID)
+
+	if p.TempF == nil || p.Humidity == nil || p.WindSpeedMph == nil {
+		return station.ErrIncomplete
+	}
+
+	// Station clocks are not trusted to be ahead
+	now := time.Now()
+	t := p.Time
+	if t.IsZero() || t.After(now) {
+		t = now
+	}
+
+	source := "PWS " + s.StationID
+
+	latest, err := u.pRepo.LatestAt(ctx, s.CityID, source)
+	if err != nil {
+		return err
+	}
+	if t.Sub(latest) < viper.GetDuration("pws.min_interval") {
+		logger.Ctx(ctx).Debug().Msg("pws.reading_throttled")
+		return nil
+	}
+
+	reading := convert(s.StationID, p)
+
+	details, err := json.Marshal(reading)
+	if err != nil {
+		return err
+	}
+
+	return u.pRepo.Save(ctx, &model.WeatherData{
+		CityID:      s.CityID,
+		Source:      source,
+		SourceType:  model.SourceTypePWS,
+		Temperature: reading.Temperature,
+		Humidity:    int(math.Round(reading.Humidity)),
+		WindSpeed:   reading.WindSpeed,
+		Details:     details,
+		CreatedAt:   t,
+	})
+}
+
+func (u *stationUseCase) List(ctx context.Context) (res []model.Station, err error) {
+	ctx, span := tracing.Start(ctx, "station.List")
+	defer func() { tracing.End(span, err) }()
+
+	return u.pRepo.List(ctx)
+}
+
+func (u *stationUseCase) Create(ctx context.Context, req model.StationRequest) (res *model.Station, err error) {
+	ctx, span := tracing.Start(ctx, "station.Create")
+	defer func() { tracing.End(span, err) }()
+
+	s := &model.Station{
+		StationID: strings.TrimSpace(req.StationID),
+		KeyHash:   hashKey(req.Key),
+		CityID:    req.CityID,
+	}
+
+	_, err = u.pRepo.GetByStationID(ctx, s.StationID)
+	if err == nil {
+		return nil, station.ErrAlreadyExists
+	}
+	if !errors.Is(err, station.ErrNotFound) {
+		return nil, err
+	}
+
+	exists, err := u.pRepo.CityExists(ctx, s.CityID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, station.ErrUnknownCity
+	}
+
+	if err := u.pRepo.Create(ctx, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (u *stationUseCase) Delete(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := tracing.Start(ctx, "station.Delete")
+	defer func() { tracing.End(span, err) }()
+
+	return u.pRepo.Delete(ctx, id)
+}
+
+// authenticate returns registered station with both ID and key matching
+func (u *stationUseCase) authenticate(ctx context.Context, stationID, key string) (*model.Station, error) {
+	if stationID == "" || key == "" {
+		return nil, station.ErrUnauthorized
+	}
+
+	s, err := u.pRepo.GetByStationID(ctx, stationID)
+	if errors.Is(err, station.ErrNotFound) {
+		return nil, station.ErrUnauthorized
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashKey(key)), []byte(s.KeyHash)) != 1 {
+		return nil, station.ErrUnauthorized
+	}
+	return s, nil
+}
+
+// hashKey is hex SHA-256 of station key, keys are not stored in plain text
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// convert turns imperial units into metric ones used by providers
+func convert(stationID string, p model.StationPush) model.StationReading {
+	return model.StationReading{
+		Station:        stationID,
+		Protocol:       p.Protocol,
+		Temperature:    *fahrenheitToCelsius(p.TempF),
+		Humidity:       *p.Humidity,
+		WindSpeed:      *scale(p.WindSpeedMph, 0.44704),
+		WindGust:       scale(p.WindGustMph, 0.44704),
+		WindDirection:  p.WindDir,
+		DewPoint:       fahrenheitToCelsius(p.DewPointF),
+		Pressure:       scale(p.BaromIn, 33.8639),
+		RainRate:       scale(p.RainRateIn, 25.4),
+		DailyRain:      scale(p.DailyRainIn, 25.4),
+		SolarRadiation: p.SolarRadiation,
+		UV:             p.UV,
+	}
+}
+
+func fahrenheitToCelsius(f *float64) *float64 {
+	if f == nil {
+		return nil
+	}
+	c := round((*f - 32) * 5 / 9)
+	return &c
+}
+
+func scale(v *float64, factor float64) *float64 {
+	if v == nil {
+		return nil
+	}
+	s := round(*v * factor)
+	return &s
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"testing"
+	"weather-data-aggregator-service/src/domain/model"
+)
+
+func TestConvert(t *testing.T) {
+	ptr := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name string
+		push model.StationPush
+		want model.StationReading
+	}{
+		{
+			name: "required fields only",
+			push: model.StationPush{
+				Protocol:     model.ProtocolWU,
+				TempF:        ptr(32),
+				Humidity:     ptr(80),
+				WindSpeedMph: ptr(0),
+			},
+			want: model.StationReading{
+				Station:     "KBERLIN1",
+				Protocol:    model.ProtocolWU,
+				Temperature: 0,
+				Humidity:    80,
+				WindSpeed:   0,
+			},
+		},
+		{
+			name: "all fields",
+			push: model.StationPush{
+				Protocol:       model.ProtocolEcowitt,
+				TempF:          ptr(68),
+				Humidity:       ptr(55),
+				DewPointF:      ptr(50),
+				WindSpeedMph:   ptr(10),
+				WindGustMph:    ptr(15),
+				WindDir:        ptr(270),
+				BaromIn:        ptr(29.92),
+				RainRateIn:     ptr(0.1),
+				DailyRainIn:    ptr(1),
+				SolarRadiation: ptr(420.5),
+				UV:             ptr(3),
+			},
+			want: model.StationReading{
+				Station:        "KBERLIN1",
+				Protocol:       model.ProtocolEcowitt,
+				Temperature:    20,
+				Humidity:       55,
+				WindSpeed:      4.47,
+				WindGust:       ptr(6.71),
+				WindDirection:  ptr(270),
+				DewPoint:       ptr(10),
+				Pressure:       ptr(1013.21),
+				RainRate:       ptr(2.54),
+				DailyRain:      ptr(25.4),
+				SolarRadiation: ptr(420.5),
+				UV:             ptr(3),
+			},
+		},
+		{
+			name: "below freezing",
+			push: model.StationPush{
+				Protocol:     model.ProtocolWU,
+				TempF:        ptr(-4),
+				Humidity:     ptr(90),
+				DewPointF:    ptr(-10.5),
+				WindSpeedMph: ptr(3.3),
+			},
+			want: model.StationReading{
+				Station:     "KBERLIN1",
+				Protocol:    model.ProtocolWU,
+				Temperature: -20,
+				Humidity:    90,
+				WindSpeed:   1.48,
+				DewPoint:    ptr(-23.61),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := convert("KBERLIN1", tt.push)
+
+			if got.Station != tt.want.Station || got.Protocol != tt.want.Protocol {
+				t.Errorf("station = %s/%s, want %s/%s", got.Station, got.Protocol, tt.want.Station, tt.want.Protocol)
+			}
+			if got.Temperature != tt.want.Temperature {
+				t.Errorf("Temperature = %v, want %v", got.Temperature, tt.want.Temperature)
+			}
+			if got.Humidity != tt.want.Humidity {
+				t.Errorf("Humidity = %v, want %v", got.Humidity, tt.want.Humidity)
+			}
+			if got.WindSpeed != tt.want.WindSpeed {
+				t.Errorf("WindSpeed = %v, want %v", got.WindSpeed, tt.want.WindSpeed)
+			}
+
+			optional := []struct {
+				field     string
+				got, want *float64
+			}{
+				{"WindGust", got.WindGust, tt.want.WindGust},
+				{"WindDirection", got.WindDirection, tt.want.WindDirection},
+				{"DewPoint", got.DewPoint, tt.want.DewPoint},
+				{"Pressure", got.Pressure, tt.want.Pressure},
+				{"RainRate", got.RainRate, tt.want.RainRate},
+				{"DailyRain", got.DailyRain, tt.want.DailyRain},
+				{"SolarRadiation", got.SolarRadiation, tt.want.SolarRadiation},
+				{"UV", got.UV, tt.want.UV},
+			}
+			for _, f := range optional {
+				switch {
+				case f.got == nil && f.want == nil:
+				case f.got == nil || f.want == nil:
+					t.Errorf("%s = %v, want %v", f.field, f.got, f.want)
+				case *f.got != *f.want:
+					t.Errorf("%s = %v, want %v", f.field, *f.got, *f.want)
+				}
+			}
+		})
+	}
+}
@@ -9,4 +9,7 @@ type Controller interface {
 	HealthCheck(c *fiber.Ctx) error
 	GetCurrent(c *fiber.Ctx) error
 	GetForecast(c *fiber.Ctx) error
+	GetHourlyForecast(c *fiber.Ctx) error
+	GetHistory(c *fiber.Ctx) error
+	GetScores(c *fiber.Ctx) error
 }
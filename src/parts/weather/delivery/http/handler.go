@@ -1,8 +1,10 @@
 package http
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
+	"time"
 	"weather-data-aggregator-service/src/domain/model"
 	"weather-data-aggregator-service/src/parts/weather"
 )
@@ -15,12 +17,18 @@ func NewWeatherController(useCase weather.UseCase) weather.Controller {
 	return &weatherController{useCase}
 }
 
-// HealthCheck
+// HealthCheck returns last fetch run and last successful fetch of every provider
 func (u *weatherController) HealthCheck(c *fiber.Ctx) error {
-	return c.JSON(fiber.Map{"message": "OK"})
+	result, err := u.useCase.GetHealth(c.UserContext())
+	if err != nil {
+		return fmt.Errorf("failed to get health: %w", err)
+	}
+
+	return c.JSON(result)
 }
 
 // GetCurrent returns current aggregated weather for specified city
+// or for tracked city nearest to lat and lon
 func (u *weatherController) GetCurrent(c *fiber.Ctx) error {
 	var q model.CurrentQuery
 
@@ -28,13 +36,25 @@ func (u *weatherController) GetCurrent(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "invalid query parameters")
 	}
 
-	if q.City == "" {
-		return fiber.NewError(fiber.StatusBadRequest, "city is required")
+	if (q.Lat == nil) != (q.Lon == nil) {
+		return fiber.NewError(fiber.StatusBadRequest, "lat and lon must be set together")
 	}
 
-	result, err := u.useCase.GetCurrent(c.Context(), q)
+	if q.City == "" && q.Lat == nil {
+		return fiber.NewError(fiber.StatusBadRequest, "city or lat and lon are required")
+	}
+
+	if !validCountry(q.Country) {
+		return fiber.NewError(fiber.StatusBadRequest, "country must be ISO 3166-1 alpha-2 code")
+	}
+
+	if q.Lat != nil && (*q.Lat < -90 || *q.Lat > 90 || *q.Lon < -180 || *q.Lon > 180) {
+		return fiber.NewError(fiber.StatusBadRequest, "lat or lon is out of range")
+	}
+
+	result, err := u.useCase.GetCurrent(c.UserContext(), q)
 	if err != nil {
-		return fmt.Errorf("failed to get current weather: %w", err)
+		return weatherError(err, "failed to get current weather")
 	}
 
 	return c.JSON(result)
@@ -52,14 +72,207 @@ func (u *weatherController) GetForecast(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "city is required")
 	}
 
-	if q.Days < 1 || q.Days > 7 {
-		return fiber.NewError(fiber.StatusBadRequest, "days must be between 1 and 7")
+	if !validCountry(q.Country) {
+		return fiber.NewError(fiber.StatusBadRequest, "country must be ISO 3166-1 alpha-2 code")
+	}
+
+	if q.Days < 1 || q.Days > 16 {
+		return fiber.NewError(fiber.StatusBadRequest, "days must be between 1 and 16")
+	}
+
+	result, err := u.useCase.GetForecast(c.UserContext(), q)
+	if err != nil {
+		return weatherError(err, "failed to get forecast")
+	}
+
+	return c.JSON(result)
+}
+
+// GetHourlyForecast returns aggregated hourly forecast for next 'hours' hours
+func (u *weatherController) GetHourlyForecast(c *fiber.Ctx) error {
+	var q model.HourlyForecastQuery
+
+	if err := c.QueryParser(&q); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid query parameters")
+	}
+
+	if q.City == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "city is required")
+	}
+
+	if !validCountry(q.Country) {
+		return fiber.NewError(fiber.StatusBadRequest, "country must be ISO 3166-1 alpha-2 code")
 	}
 
-	result, err := u.useCase.GetForecast(c.Context(), q)
+	if q.Hours == 0 {
+		q.Hours = 24
+	}
+	if q.Hours < 1 || q.Hours > 384 {
+		return fiber.NewError(fiber.StatusBadRequest, "hours must be between 1 and 384")
+	}
+
+	result, err := u.useCase.GetHourlyForecast(c.UserContext(), q)
 	if err != nil {
-		return fmt.Errorf("failed to get forecast: %w", err)
+		return weatherError(err, "failed to get hourly forecast")
 	}
 
 	return c.JSON(result)
 }
+
+// GetHistory returns time-bucketed weather statistics for specified city and range
+func (u *weatherController) GetHistory(c *fiber.Ctx) error {
+	var q model.HistoryQuery
+
+	if err := c.QueryParser(&q); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid query parameters")
+	}
+
+	if q.City == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "city is required")
+	}
+
+	if !validCountry(q.Country) {
+		return fiber.NewError(fiber.StatusBadRequest, "country must be ISO 3166-1 alpha-2 code")
+	}
+
+	f := model.HistoryFilter{
+		City:        q.City,
+		Country:     q.Country,
+		To:          time.Now(),
+		Granularity: q.Granularity,
+		Source:      q.Source,
+		Limit:       q.Limit,
+	}
+
+	if q.To != "" {
+		to, err := time.Parse(time.RFC3339, q.To)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "to must be RFC3339 time")
+		}
+		f.To = to
+	}
+
+	f.From = f.To.Add(-24 * time.Hour)
+	if q.From != "" {
+		from, err := time.Parse(time.RFC3339, q.From)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "from must be RFC3339 time")
+		}
+		f.From = from
+	}
+
+	if !f.From.Before(f.To) {
+		return fiber.NewError(fiber.StatusBadRequest, "from must be before to")
+	}
+
+	switch f.Granularity {
+	case "":
+		f.Granularity = model.GranularityHourly
+	case model.GranularityRaw, model.GranularityHourly, model.GranularityDaily:
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, "granularity must be one of raw, hourly, daily")
+	}
+
+	if f.Limit == 0 {
+		f.Limit = 100
+	}
+	if f.Limit < 1 || f.Limit > 1000 {
+		return fiber.NewError(fiber.StatusBadRequest, "limit must be between 1 and 1000")
+	}
+
+	if q.Cursor != "" {
+		after, err := model.DecodeHistoryCursor(q.Cursor)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid cursor")
+		}
+		f.After = after
+	}
+
+	result, err := u.useCase.GetHistory(c.UserContext(), f)
+	if err != nil {
+		return weatherError(err, "failed to get history")
+	}
+
+	return c.JSON(result)
+}
+
+// GetScores returns provider errors against METAR observations, last 7 days by default
+func (u *weatherController) GetScores(c *fiber.Ctx) error {
+	var q model.ScoresQuery
+
+	if err := c.QueryParser(&q); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid query parameters")
+	}
+
+	if q.City == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "city is required")
+	}
+
+	if !validCountry(q.Country) {
+		return fiber.NewError(fiber.StatusBadRequest, "country must be ISO 3166-1 alpha-2 code")
+	}
+
+	f := model.ScoresFilter{
+		City:    q.City,
+		Country: q.Country,
+		To:      time.Now(),
+	}
+
+	if q.To != "" {
+		to, err := time.Parse(time.RFC3339, q.To)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "to must be RFC3339 time")
+		}
+		f.To = to
+	}
+
+	f.From = f.To.Add(-7 * 24 * time.Hour)
+	if q.From != "" {
+		from, err := time.Parse(time.RFC3339, q.From)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "from must be RFC3339 time")
+		}
+		f.From = from
+	}
+
+	if !f.From.Before(f.To) {
+		return fiber.NewError(fiber.StatusBadRequest, "from must be before to")
+	}
+
+	result, err := u.useCase.GetScores(c.UserContext(), f)
+	if err != nil {
+		return weatherError(err, "failed to get scores")
+	}
+
+	return c.JSON(result)
+}
+
+// weatherError maps domain errors to HTTP status codes
+func weatherError(err error, msg string) error {
+	switch {
+	case errors.Is(err, weather.ErrRangeNotSupported):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, weather.ErrAmbiguousCity):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	case errors.Is(err, weather.ErrCityNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+}
+
+// validCountry accepts empty or two letter country code
+func validCountry(country string) bool {
+	if country == "" {
+		return true
+	}
+	if len(country) != 2 {
+		return false
+	}
+	for _, r := range country {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
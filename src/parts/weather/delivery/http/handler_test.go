@@ -0,0 +1,172 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/parts/weather"
+)
+
+// historyUseCase serves history of "London" from memory, querying buckets
+// after cursor like repository does
+type historyUseCase struct {
+	weather.UseCase
+	buckets []model.HistoryBucket
+}
+
+func (u *historyUseCase) GetHistory(_ context.Context, f model.HistoryFilter) (*model.WeatherHistory, error) {
+	switch f.City {
+	case "London":
+	case "Paris":
+		return nil, weather.ErrAmbiguousCity
+	default:
+		return nil, weather.ErrCityNotFound
+	}
+
+	var rows []model.HistoryBucket
+	for _, b := range u.buckets {
+		if b.Time.Before(f.From) || !b.Time.Before(f.To) || !b.Time.After(f.After) {
+			continue
+		}
+		if len(rows) == f.Limit+1 {
+			break
+		}
+		rows = append(rows, b)
+	}
+
+	res := &model.WeatherHistory{City: f.City, Granularity: f.Granularity}
+	res.SetPage(rows, f.Limit)
+	return res, nil
+}
+
+func (u *historyUseCase) GetScores(_ context.Context, f model.ScoresFilter) (*model.ProviderScores, error) {
+	return nil, weather.ErrCityNotFound
+}
+
+func newHistoryApp(buckets []model.HistoryBucket) *fiber.App {
+	ctrl := NewWeatherController(&historyUseCase{buckets: buckets})
+
+	app := fiber.New()
+	app.Get("/history", ctrl.GetHistory)
+	app.Get("/scores", ctrl.GetScores)
+	return app
+}
+
+func TestGetHistoryPages(t *testing.T) {
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	var buckets []model.HistoryBucket
+	for i := 0; i < 5; i++ {
+		buckets = append(buckets, model.HistoryBucket{Time: start.Add(time.Duration(i) * time.Hour), Samples: 1})
+	}
+	app := newHistoryApp(buckets)
+
+	params := url.Values{}
+	params.Set("city", "London")
+	params.Set("from", start.Format(time.RFC3339))
+	params.Set("to", start.Add(24*time.Hour).Format(time.RFC3339))
+	params.Set("limit", "2")
+
+	var (
+		pages []int
+		seen  []time.Time
+	)
+	for {
+		var page model.WeatherHistory
+		if code := get(t, app, "/history?"+params.Encode(), &page); code != 200 {
+			t.Fatalf("page %d status = %d, want 200", len(pages)+1, code)
+		}
+
+		pages = append(pages, len(page.Buckets))
+		for _, b := range page.Buckets {
+			seen = append(seen, b.Time)
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		if len(pages) > len(buckets) {
+			t.Fatal("paging does not end")
+		}
+		params.Set("cursor", page.NextCursor)
+	}
+
+	if want := []int{2, 2, 1}; len(pages) != len(want) || pages[0] != want[0] || pages[1] != want[1] || pages[2] != want[2] {
+		t.Errorf("page sizes = %v, want %v", pages, want)
+	}
+	if len(seen) != len(buckets) {
+		t.Fatalf("buckets = %d, want %d", len(seen), len(buckets))
+	}
+	for i, b := range buckets {
+		if !seen[i].Equal(b.Time) {
+			t.Errorf("bucket %d = %s, want %s", i, seen[i], b.Time)
+		}
+	}
+}
+
+func TestGetHistoryLastPageHasNoCursor(t *testing.T) {
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	app := newHistoryApp([]model.HistoryBucket{{Time: start}, {Time: start.Add(time.Hour)}})
+
+	params := url.Values{}
+	params.Set("city", "London")
+	params.Set("from", start.Format(time.RFC3339))
+	params.Set("to", start.Add(24*time.Hour).Format(time.RFC3339))
+	// Page exactly as large as the rest
+	params.Set("limit", "2")
+
+	var page model.WeatherHistory
+	if code := get(t, app, "/history?"+params.Encode(), &page); code != 200 {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	if len(page.Buckets) != 2 || page.NextCursor != "" {
+		t.Errorf("buckets = %d, next_cursor = %q, want 2 and none", len(page.Buckets), page.NextCursor)
+	}
+}
+
+func TestGetHistoryErrors(t *testing.T) {
+	app := newHistoryApp(nil)
+
+	tests := []struct {
+		name string
+		path string
+		code int
+	}{
+		{name: "bad cursor", path: "/history?city=London&cursor=not-a-cursor", code: 400},
+		{name: "cursor is not time", path: "/history?city=London&cursor=" + url.QueryEscape("cGFnZS0y"), code: 400},
+		{name: "unknown city", path: "/history?city=Atlantis", code: 404},
+		{name: "ambiguous city", path: "/history?city=Paris", code: 409},
+		{name: "invalid country", path: "/history?city=Paris&country=USA", code: 400},
+		{name: "unknown city scores", path: "/scores?city=Atlantis", code: 404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := get(t, app, tt.path, nil); code != tt.code {
+				t.Errorf("GET %s status = %d, want %d", tt.path, code, tt.code)
+			}
+		})
+	}
+}
+
+func get(t *testing.T, app *fiber.App, path string, out interface{}) int {
+	t.Helper()
+
+	resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	defer resp.Body.Close()
+
+	if out != nil && resp.StatusCode == 200 {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("decode %s: %v", path, err)
+		}
+	}
+	return resp.StatusCode
+}
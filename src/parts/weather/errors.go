@@ -0,0 +1,9 @@
+package weather
+
+import "errors"
+
+var (
+	ErrRangeNotSupported = errors.New("no enabled provider covers requested range")
+	ErrAmbiguousCity     = errors.New("city name is tracked in several countries, country is required")
+	ErrCityNotFound      = errors.New("city is not tracked")
+)
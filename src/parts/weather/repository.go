@@ -9,4 +9,14 @@ import (
 type PostgresRepository interface {
 	GetCurrent(ctx context.Context, q model.CurrentQuery) (*model.AggregatedWeatherDataResp, error)
 	GetForecast(ctx context.Context, q model.ForecastQuery) (*model.AggregatedForecast, error)
+	GetHourlyForecast(ctx context.Context, q model.HourlyForecastQuery) (*model.AggregatedHourlyForecast, error)
+	GetHistory(ctx context.Context, f model.HistoryFilter) (*model.WeatherHistory, error)
+	GetScores(ctx context.Context, f model.ScoresFilter) (*model.ProviderScores, error)
+	GetHealth(ctx context.Context) (*model.HealthStatus, error)
+}
+
+// CacheRepository represent cache decorator contract
+type CacheRepository interface {
+	PostgresRepository
+	Invalidate(ctx context.Context, city *model.City) error
 }
@@ -2,11 +2,14 @@ package postgres
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
 	"github.com/uptrace/bun"
-	"net/http"
-	"net/url"
+	"math"
+	"sort"
+	"strings"
 	"time"
 	"weather-data-aggregator-service/src/domain/model"
 	weather_client "weather-data-aggregator-service/src/infrastructure/weather"
@@ -22,160 +25,456 @@ func NewWeatherPostgresRepository(db *bun.DB, weatherClient *weather_client.Weat
 	return &weatherPostgresRepository{db, weatherClient}
 }
 func (w *weatherPostgresRepository) GetCurrent(ctx context.Context, q model.CurrentQuery) (*model.AggregatedWeatherDataResp, error) {
+	if q.Lat != nil && q.Lon != nil {
+		return w.getCurrentByCoord(ctx, model.Coord{Lat: *q.Lat, Lon: *q.Lon})
+	}
+
+	city, err := w.cityByName(ctx, q.City, q.Country)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.getLatestAggregate(ctx, city.ID)
+}
 
-	var city model.City
-	err := w.db.NewSelect().Model(&city).Where("name = ?", q.City).Scan(ctx)
+// getCurrentByCoord returns data of nearest tracked city within
+// weather.nearest_max_distance_km, otherwise fetches location on demand
+func (w *weatherPostgresRepository) getCurrentByCoord(ctx context.Context, coord model.Coord) (*model.AggregatedWeatherDataResp, error) {
+	viper.SetDefault("weather.nearest_max_distance_km", 50)
+
+	city, distance, ok := w.weatherClient.NearestCity(coord)
+	if ok && distance <= viper.GetFloat64("weather.nearest_max_distance_km") {
+		res, err := w.getLatestAggregate(ctx, city.ID)
+		if err == nil {
+			distance = round(distance)
+			res.DistanceKm = &distance
+			return res, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+	}
+
+	location := &model.City{Latitude: &coord.Lat, Longitude: &coord.Lon}
+	awd, err := w.weatherClient.FetchCurrent(ctx, location)
 	if err != nil {
 		return nil, err
 	}
 
+	res := aggregateResp(awd)
+	res.City = location
+	res.OnDemand = true
+	return res, nil
+}
+
+func (w *weatherPostgresRepository) getLatestAggregate(ctx context.Context, cityID uuid.UUID) (*model.AggregatedWeatherDataResp, error) {
 	var awd model.AggregatedWeatherData
-	err = w.db.NewSelect().Model(&awd).Relation("City").Where("city_id = ?", city.ID).
+	err := w.db.NewSelect().Model(&awd).Relation("City").Where("city_id = ?", cityID).
 		Order("created_at DESC").Limit(1).Scan(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	res := model.AggregatedWeatherDataResp{
+	return aggregateResp(&awd), nil
+}
+
+func aggregateResp(awd *model.AggregatedWeatherData) *model.AggregatedWeatherDataResp {
+	return &model.AggregatedWeatherDataResp{
 		City:        awd.City,
 		Temperature: awd.Temperature,
 		Humidity:    awd.Humidity,
 		WindSpeed:   awd.WindSpeed,
+		Spread: model.Spread{
+			Temperature: awd.TemperatureSpread,
+			Humidity:    awd.HumiditySpread,
+			WindSpeed:   awd.WindSpeedSpread,
+		},
+		Confidence:     awd.Confidence,
+		Sources:        awd.Sources,
+		MissingSources: awd.MissingSources,
 	}
-	return &res, nil
 }
-func (w *weatherPostgresRepository) GetForecast(ctx context.Context, q model.ForecastQuery) (*model.AggregatedForecast, error) {
-	escapedCity := url.QueryEscape(q.City)
-
-	//openweathermap - paid service
-	//owmData, err := fetchOWMForecast(escapedCity, w.weatherClient.GetOpenWeatherAPIKeyKey(), q.Days)
-	//if err != nil {
-	//	return nil, err
-	//}
 
-	apiData, err := fetchWeatherAPIForecast(escapedCity, w.weatherClient.GetWeatherAPIKey(), q.Days)
+func (w *weatherPostgresRepository) GetForecast(ctx context.Context, q model.ForecastQuery) (*model.AggregatedForecast, error) {
+	city, err := w.findCity(ctx, q.City, q.Country)
 	if err != nil {
 		return nil, err
 	}
 
-	//openweathermap - paid service
-	//if len(owmData) != len(apiData) {
-	//	return nil, fmt.Errorf("mismatch in days length between APIs")
-	//}
-
-	//aggregated := make([]model.AggregatedForecastDay, len(owmData))
-	//for i := 0; i < len(owmData); i++ {
-	//	aggregated[i] = model.AggregatedForecastDay{
-	//		Date:         owmData[i].Date,
-	//		Temperature:  (owmData[i].Temperature + apiData[i].Temperature) / 2,
-	//		Humidity:     (owmData[i].Humidity + apiData[i].Humidity) / 2,
-	//		WindSpeed:    (owmData[i].WindSpeed + apiData[i].WindSpeed) / 2,
-	//		Descriptions: []string{owmData[i].Description, apiData[i].Description},
-	//	}
-	//}
-
-	aggregated := make([]model.AggregatedForecastDay, len(apiData))
-	for i := 0; i < len(apiData); i++ {
-		aggregated[i] = model.AggregatedForecastDay{
-			Date:         apiData[i].Date,
-			Temperature:  apiData[i].Temperature,
-			Humidity:     apiData[i].Humidity,
-			WindSpeed:    apiData[i].WindSpeed,
-			Descriptions: []string{apiData[i].Description},
-		}
+	forecasts, err := w.weatherClient.FetchForecast(ctx, city, q.Days)
+	if errors.Is(err, weather_client.ErrRangeNotSupported) {
+		return nil, weather.ErrRangeNotSupported
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	return &model.AggregatedForecast{
 		City: q.City,
-		Days: aggregated,
+		Days: aggregateForecast(forecasts),
 	}, nil
 }
 
-func fetchOWMForecast(cityName, apiKey string, days int) ([]model.ForecastDay, error) {
-	if days < 1 || days > 7 {
-		return nil, fmt.Errorf("days must be between 1 and 7")
+func (w *weatherPostgresRepository) GetHourlyForecast(ctx context.Context, q model.HourlyForecastQuery) (*model.AggregatedHourlyForecast, error) {
+	city, err := w.findCity(ctx, q.City, q.Country)
+	if err != nil {
+		return nil, err
 	}
 
-	cityURL := fmt.Sprintf("https://api.openweathermap.org/data/2.5/weather?q=%s&appid=%s&units=metric", cityName, apiKey)
-	resp, err := http.Get(cityURL)
+	forecasts, err := w.weatherClient.FetchHourly(ctx, city, q.Hours)
+	if errors.Is(err, weather_client.ErrRangeNotSupported) {
+		return nil, weather.ErrRangeNotSupported
+	}
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var cityResp model.СityResp
-	if err := json.NewDecoder(resp.Body).Decode(&cityResp); err != nil {
+	return &model.AggregatedHourlyForecast{
+		City:  q.City,
+		Hours: aggregateHourly(forecasts),
+	}, nil
+}
+
+// findCity returns tracked city or geocodes untracked one by name
+func (w *weatherPostgresRepository) findCity(ctx context.Context, name, country string) (*model.City, error) {
+	city, err := w.cityByName(ctx, name, country)
+	if errors.Is(err, weather.ErrCityNotFound) {
+		return w.weatherClient.Geocode(ctx, name, strings.ToUpper(country))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !city.HasCoord() {
+		return w.weatherClient.Geocode(ctx, name, city.Country)
+	}
+	return city, nil
+}
+
+// cityByName returns tracked city by name, country is required only when
+// the name is tracked in several countries
+func (w *weatherPostgresRepository) cityByName(ctx context.Context, name, country string) (*model.City, error) {
+	var cities []model.City
+
+	query := w.db.NewSelect().Model(&cities).Where("lower(name) = lower(?)", name)
+	if country != "" {
+		query = query.Where("country = upper(?)", country)
+	}
+
+	if err := query.Limit(2).Scan(ctx); err != nil {
 		return nil, err
 	}
 
-	oneCallURL := fmt.Sprintf(
-		"https://api.openweathermap.org/data/2.5/onecall?lat=%f&lon=%f&exclude=minutely,hourly,alerts,current&units=metric&appid=%s",
-		cityResp.Coord.Lat, cityResp.Coord.Lon, apiKey,
-	)
+	switch len(cities) {
+	case 0:
+		return nil, weather.ErrCityNotFound
+	case 1:
+		return &cities[0], nil
+	default:
+		return nil, weather.ErrAmbiguousCity
+	}
+}
 
-	resp2, err := http.Get(oneCallURL)
+type historyRow struct {
+	Time           time.Time `bun:"time"`
+	Samples        int       `bun:"samples"`
+	TemperatureMin float64   `bun:"temperature_min"`
+	TemperatureAvg float64   `bun:"temperature_avg"`
+	TemperatureMax float64   `bun:"temperature_max"`
+	HumidityMin    float64   `bun:"humidity_min"`
+	HumidityAvg    float64   `bun:"humidity_avg"`
+	HumidityMax    float64   `bun:"humidity_max"`
+	WindSpeedMin   float64   `bun:"wind_speed_min"`
+	WindSpeedAvg   float64   `bun:"wind_speed_avg"`
+	WindSpeedMax   float64   `bun:"wind_speed_max"`
+}
+
+func (w *weatherPostgresRepository) GetHistory(ctx context.Context, f model.HistoryFilter) (*model.WeatherHistory, error) {
+	city, err := w.cityByName(ctx, f.City, f.Country)
 	if err != nil {
 		return nil, err
 	}
-	defer resp2.Body.Close()
 
-	var oneCallResp model.OneCallRespOWM
-	if err := json.NewDecoder(resp2.Body).Decode(&oneCallResp); err != nil {
+	bucket := "d.created_at"
+	switch f.Granularity {
+	case model.GranularityHourly:
+		bucket = "date_trunc('hour', d.created_at)"
+	case model.GranularityDaily:
+		bucket = "date_trunc('day', d.created_at)"
+	}
+
+	table := "aggregated_weather_data"
+	if f.Source != "" {
+		table = "weather_data"
+	}
+
+	query := w.db.NewSelect().
+		TableExpr("? AS d", bun.Ident(table)).
+		ColumnExpr("? AS time", bun.Safe(bucket)).
+		ColumnExpr("count(*) AS samples")
+	for _, metric := range []string{"temperature", "humidity", "wind_speed"} {
+		query = query.
+			ColumnExpr("min(d.?) AS ?", bun.Ident(metric), bun.Ident(metric+"_min")).
+			ColumnExpr("avg(d.?) AS ?", bun.Ident(metric), bun.Ident(metric+"_avg")).
+			ColumnExpr("max(d.?) AS ?", bun.Ident(metric), bun.Ident(metric+"_max"))
+	}
+
+	query = query.
+		Where("d.city_id = ?", city.ID).
+		Where("d.created_at >= ?", f.From).
+		Where("d.created_at < ?", f.To)
+
+	if f.Source != "" {
+		query = query.Where("lower(d.source) = lower(?)", f.Source)
+	}
+	if !f.After.IsZero() {
+		query = query.Where("? > ?", bun.Safe(bucket), f.After)
+	}
+
+	// One extra row tells whether next page exists
+	var rows []historyRow
+	err = query.GroupExpr("1").OrderExpr("1 ASC").Limit(f.Limit+1).Scan(ctx, &rows)
+	if err != nil {
 		return nil, err
 	}
 
-	if len(oneCallResp.Daily) < days {
-		days = len(oneCallResp.Daily)
+	res := &model.WeatherHistory{
+		City:        city.Name,
+		Granularity: f.Granularity,
+		Source:      f.Source,
 	}
 
-	result := make([]model.ForecastDay, days)
-	for i := 0; i < days; i++ {
-		d := oneCallResp.Daily[i]
-		desc := ""
-		if len(d.Weather) > 0 {
-			desc = d.Weather[0].Description
-		}
-		result[i] = model.ForecastDay{
-			Date:        time.Unix(d.Dt, 0),
-			Temperature: d.Temp.Day,
-			Humidity:    d.Humidity,
-			WindSpeed:   d.WindSpeed,
-			Description: desc,
-		}
+	buckets := make([]model.HistoryBucket, 0, len(rows))
+	for _, r := range rows {
+		buckets = append(buckets, model.HistoryBucket{
+			Time:        r.Time,
+			Samples:     r.Samples,
+			Temperature: model.MetricStats{Min: r.TemperatureMin, Avg: round(r.TemperatureAvg), Max: r.TemperatureMax},
+			Humidity:    model.MetricStats{Min: r.HumidityMin, Avg: round(r.HumidityAvg), Max: r.HumidityMax},
+			WindSpeed:   model.MetricStats{Min: r.WindSpeedMin, Avg: round(r.WindSpeedAvg), Max: r.WindSpeedMax},
+		})
 	}
+	res.SetPage(buckets, f.Limit)
+
+	return res, nil
+}
 
-	return result, nil
+type scoreRow struct {
+	Source          string  `bun:"source"`
+	Samples         int     `bun:"samples"`
+	TemperatureMAE  float64 `bun:"temperature_mae"`
+	TemperatureBias float64 `bun:"temperature_bias"`
+	HumidityMAE     float64 `bun:"humidity_mae"`
+	WindSpeedMAE    float64 `bun:"wind_speed_mae"`
 }
 
-func fetchWeatherAPIForecast(cityName, apiKey string, days int) ([]model.ForecastDay, error) {
-	if days < 1 || days > 7 {
-		return nil, fmt.Errorf("days must be between 1 and 7")
+// GetScores compares every provider reading with METAR observation of the city
+// nearest in time within scoring.match_window
+func (w *weatherPostgresRepository) GetScores(ctx context.Context, f model.ScoresFilter) (*model.ProviderScores, error) {
+	viper.SetDefault("scoring.match_window", 30*time.Minute)
+
+	city, err := w.cityByName(ctx, f.City, f.Country)
+	if err != nil {
+		return nil, err
 	}
 
-	url := fmt.Sprintf("https://api.weatherapi.com/v1/forecast.json?key=%s&q=%s&days=%d", apiKey, cityName, days)
-	resp, err := http.Get(url)
+	window := viper.GetDuration("scoring.match_window").Seconds()
+
+	observation := w.db.NewSelect().
+		TableExpr("weather_data AS o").
+		Column("o.temperature", "o.humidity", "o.wind_speed").
+		Where("o.city_id = p.city_id").
+		Where("o.source_type = ?", model.SourceTypeMETAR).
+		Where("o.created_at BETWEEN p.created_at - ? * interval '1 second' AND p.created_at + ? * interval '1 second'", window, window).
+		OrderExpr("abs(extract(epoch FROM o.created_at - p.created_at))").
+		Limit(1)
+
+	var rows []scoreRow
+	err = w.db.NewSelect().
+		TableExpr("weather_data AS p").
+		Join("JOIN LATERAL (?) AS o ON true", observation).
+		ColumnExpr("p.source").
+		ColumnExpr("count(*) AS samples").
+		ColumnExpr("avg(abs(p.temperature - o.temperature)) AS temperature_mae").
+		ColumnExpr("avg(p.temperature - o.temperature) AS temperature_bias").
+		ColumnExpr("avg(abs(p.humidity - o.humidity)) AS humidity_mae").
+		ColumnExpr("avg(abs(p.wind_speed - o.wind_speed)) AS wind_speed_mae").
+		Where("p.city_id = ?", city.ID).
+		Where("p.source_type = ?", model.SourceTypeProvider).
+		Where("p.created_at >= ?", f.From).
+		Where("p.created_at < ?", f.To).
+		GroupExpr("p.source").
+		OrderExpr("temperature_mae ASC").
+		Scan(ctx, &rows)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var apiResp model.OneCallRespWA
+	res := &model.ProviderScores{
+		City:        city.Name,
+		From:        f.From,
+		To:          f.To,
+		GroundTruth: "METAR",
+		Scores:      make([]model.ProviderScore, 0, len(rows)),
+	}
+
+	for _, r := range rows {
+		res.Scores = append(res.Scores, model.ProviderScore{
+			Source:          r.Source,
+			Samples:         r.Samples,
+			TemperatureMAE:  round(r.TemperatureMAE),
+			TemperatureBias: round(r.TemperatureBias),
+			HumidityMAE:     round(r.HumidityMAE),
+			WindSpeedMAE:    round(r.WindSpeedMAE),
+		})
+	}
+
+	return res, nil
+}
+
+// GetHealth returns latest fetch run and time of last successful fetch by provider
+// within journal.health_window, providers without success in window are omitted
+func (w *weatherPostgresRepository) GetHealth(ctx context.Context) (*model.HealthStatus, error) {
+	viper.SetDefault("journal.health_window", 24*time.Hour)
+
+	res := &model.HealthStatus{
+		Message:     "OK",
+		LastSuccess: make(map[string]time.Time),
+	}
+
+	var run model.FetchRun
+	err := w.db.NewSelect().Model(&run).Order("started_at DESC").Limit(1).Scan(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err == nil {
+		res.LastRun = &run
+	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+	var rows []struct {
+		Provider string    `bun:"provider"`
+		LastAt   time.Time `bun:"last_at"`
+	}
+	err = w.db.NewSelect().
+		TableExpr("fetch_run_attempts").
+		ColumnExpr("provider").
+		ColumnExpr("max(created_at) AS last_at").
+		Where("status = ?", model.FetchAttemptOK).
+		Where("created_at >= ?", time.Now().Add(-viper.GetDuration("journal.health_window"))).
+		Group("provider").
+		Scan(ctx, &rows)
+	if err != nil {
 		return nil, err
 	}
 
-	result := make([]model.ForecastDay, len(apiResp.Forecast.Forecastday))
-	for i, d := range apiResp.Forecast.Forecastday {
-		date, _ := time.Parse("2006-01-02", d.Date)
-		result[i] = model.ForecastDay{
-			Date:        date,
-			Temperature: d.Day.AvgtempC,
-			Humidity:    int(d.Day.Avghumidity),
-			WindSpeed:   d.Day.MaxwindKph / 3.6, // конвертируем km/h в m/s
-			Description: d.Day.Condition.Text,
+	for _, r := range rows {
+		res.LastSuccess[r.Provider] = r.LastAt
+	}
+
+	return res, nil
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// aggregateForecast averages provider forecasts day by day
+func aggregateForecast(forecasts map[string][]model.ForecastDay) []model.AggregatedForecastDay {
+	names := make([]string, 0, len(forecasts))
+	for name := range forecasts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	byDate := make(map[string][]model.ForecastDay)
+	for _, name := range names {
+		for _, d := range forecasts[name] {
+			key := d.Date.Format("2006-01-02")
+			byDate[key] = append(byDate[key], d)
+		}
+	}
+
+	dates := make([]string, 0, len(byDate))
+	for date := range byDate {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	aggregated := make([]model.AggregatedForecastDay, len(dates))
+	for i, date := range dates {
+		days := byDate[date]
+
+		var (
+			temperature, windSpeed float64
+			humidity               int
+			descriptions           []string
+		)
+		for _, d := range days {
+			temperature += d.Temperature
+			humidity += d.Humidity
+			windSpeed += d.WindSpeed
+			descriptions = append(descriptions, d.Description)
+		}
+
+		n := len(days)
+		aggregated[i] = model.AggregatedForecastDay{
+			Date:         days[0].Date,
+			Temperature:  temperature / float64(n),
+			Humidity:     humidity / n,
+			WindSpeed:    math.Round((windSpeed/float64(n))*100) / 100,
+			Descriptions: descriptions,
+		}
+	}
+
+	return aggregated
+}
+
+// aggregateHourly averages provider forecasts hour by hour
+func aggregateHourly(forecasts map[string][]model.ForecastHour) []model.AggregatedForecastHour {
+	names := make([]string, 0, len(forecasts))
+	for name := range forecasts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	byTime := make(map[time.Time][]model.ForecastHour)
+	for _, name := range names {
+		for _, h := range forecasts[name] {
+			key := h.Time.UTC().Truncate(time.Hour)
+			byTime[key] = append(byTime[key], h)
+		}
+	}
+
+	times := make([]time.Time, 0, len(byTime))
+	for t := range byTime {
+		times = append(times, t)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	aggregated := make([]model.AggregatedForecastHour, len(times))
+	for i, t := range times {
+		hours := byTime[t]
+
+		var (
+			temperature, windSpeed float64
+			humidity               int
+			descriptions           []string
+		)
+		for _, h := range hours {
+			temperature += h.Temperature
+			humidity += h.Humidity
+			windSpeed += h.WindSpeed
+			descriptions = append(descriptions, h.Description)
+		}
+
+		n := len(hours)
+		aggregated[i] = model.AggregatedForecastHour{
+			Time:         t,
+			Temperature:  round(temperature / float64(n)),
+			Humidity:     humidity / n,
+			WindSpeed:    round(windSpeed / float64(n)),
+			Descriptions: descriptions,
 		}
 	}
 
-	return result, nil
+	return aggregated
 }
@@ -0,0 +1,162 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/spf13/viper"
+	"strings"
+	"time"
+	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/infrastructure/logger"
+	"weather-data-aggregator-service/src/infrastructure/metrics"
+	"weather-data-aggregator-service/src/parts/weather"
+)
+
+type weatherCacheRepository struct {
+	rdb         *redis.Client
+	next        weather.PostgresRepository
+	currentTTL  time.Duration
+	coordTTL    time.Duration
+	forecastTTL time.Duration
+}
+
+// NewWeatherCacheRepository wraps repository with read-through redis cache
+func NewWeatherCacheRepository(rdb *redis.Client, next weather.PostgresRepository) weather.CacheRepository {
+	viper.SetDefault("cache.current_ttl", 15*time.Minute)
+	viper.SetDefault("cache.forecast_ttl", time.Hour)
+	viper.SetDefault("cache.coord_ttl", 5*time.Minute)
+
+	return &weatherCacheRepository{
+		rdb:         rdb,
+		next:        next,
+		currentTTL:  viper.GetDuration("cache.current_ttl"),
+		coordTTL:    viper.GetDuration("cache.coord_ttl"),
+		forecastTTL: viper.GetDuration("cache.forecast_ttl"),
+	}
+}
+
+func (w *weatherCacheRepository) GetCurrent(ctx context.Context, q model.CurrentQuery) (*model.AggregatedWeatherDataResp, error) {
+	var res model.AggregatedWeatherDataResp
+
+	key, ttl, cache := currentKey(q.City, q.Country), w.currentTTL, "current"
+	if q.Lat != nil && q.Lon != nil {
+		key, ttl, cache = coordKey(*q.Lat, *q.Lon), w.coordTTL, "coord"
+	}
+
+	err := w.readThrough(ctx, key, ttl, cache, &res, func() (interface{}, error) {
+		return w.next.GetCurrent(ctx, q)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (w *weatherCacheRepository) GetForecast(ctx context.Context, q model.ForecastQuery) (*model.AggregatedForecast, error) {
+	var res model.AggregatedForecast
+
+	err := w.readThrough(ctx, forecastKey(q.City, q.Country, q.Days), w.forecastTTL, "forecast", &res, func() (interface{}, error) {
+		return w.next.GetForecast(ctx, q)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (w *weatherCacheRepository) GetHourlyForecast(ctx context.Context, q model.HourlyForecastQuery) (*model.AggregatedHourlyForecast, error) {
+	var res model.AggregatedHourlyForecast
+
+	err := w.readThrough(ctx, hourlyKey(q.City, q.Country, q.Hours), w.forecastTTL, "hourly", &res, func() (interface{}, error) {
+		return w.next.GetHourlyForecast(ctx, q)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// GetHistory is not cached, ranges are too diverse to reuse
+func (w *weatherCacheRepository) GetHistory(ctx context.Context, f model.HistoryFilter) (*model.WeatherHistory, error) {
+	return w.next.GetHistory(ctx, f)
+}
+
+// GetScores is not cached, it is not requested often
+func (w *weatherCacheRepository) GetScores(ctx context.Context, f model.ScoresFilter) (*model.ProviderScores, error) {
+	return w.next.GetScores(ctx, f)
+}
+
+// GetHealth is not cached, it must reflect latest fetch runs
+func (w *weatherCacheRepository) GetHealth(ctx context.Context) (*model.HealthStatus, error) {
+	return w.next.GetHealth(ctx)
+}
+
+// Invalidate drops cached current weather of city requested with
+// and without country
+func (w *weatherCacheRepository) Invalidate(ctx context.Context, city *model.City) error {
+	return w.rdb.Del(ctx, currentKey(city.Name, ""), currentKey(city.Name, city.Country)).Err()
+}
+
+// readThrough decodes cached value into out or loads it and stores in cache.
+// Redis failures are logged and never fail request.
+func (w *weatherCacheRepository) readThrough(ctx context.Context, key string, ttl time.Duration, cache string, out interface{}, load func() (interface{}, error)) error {
+	cached, err := w.rdb.Get(ctx, key).Bytes()
+	if err == nil {
+		if err = json.Unmarshal(cached, out); err == nil {
+			metrics.CacheHit(cache, true)
+			return nil
+		}
+	}
+	if !errors.Is(err, redis.Nil) {
+		logger.Ctx(ctx).Error().Err(err).Str("key", key).Msg("cache.read_failed")
+	}
+	metrics.CacheHit(cache, false)
+
+	value, err := load()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	if err := w.rdb.Set(ctx, key, data, ttl).Err(); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Str("key", key).Msg("cache.write_failed")
+	}
+
+	return json.Unmarshal(data, out)
+}
+
+// normalizeCity joins name and optional country, e.g. "paris" or "paris,us"
+func normalizeCity(city, country string) string {
+	city = strings.ToLower(strings.TrimSpace(city))
+	if country != "" {
+		city += "," + strings.ToLower(country)
+	}
+	return city
+}
+
+func currentKey(city, country string) string {
+	return fmt.Sprintf("weather:current:%s", normalizeCity(city, country))
+}
+
+func forecastKey(city, country string, days int) string {
+	return fmt.Sprintf("weather:forecast:%s:%d", normalizeCity(city, country), days)
+}
+
+func hourlyKey(city, country string, hours int) string {
+	return fmt.Sprintf("weather:hourly:%s:%d", normalizeCity(city, country), hours)
+}
+
+// coordKey rounds location to ~100m so nearby requests share cache
+func coordKey(lat, lon float64) string {
+	return fmt.Sprintf("weather:current:coord:%.3f,%.3f", lat, lon)
+}
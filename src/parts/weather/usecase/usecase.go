@@ -2,7 +2,10 @@ package usecase
 
 import (
 	"context"
+	"go.opentelemetry.io/otel/attribute"
 	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/infrastructure/logger"
+	"weather-data-aggregator-service/src/infrastructure/tracing"
 	"weather-data-aggregator-service/src/parts/weather"
 )
 
@@ -14,10 +17,56 @@ func NewWeatherUseCase(pRepo weather.PostgresRepository) weather.UseCase {
 	return &weatherUseCase{pRepo}
 }
 
-func (w *weatherUseCase) GetCurrent(ctx context.Context, q model.CurrentQuery) (*model.AggregatedWeatherDataResp, error) {
+func (w *weatherUseCase) GetCurrent(ctx context.Context, q model.CurrentQuery) (res *model.AggregatedWeatherDataResp, err error) {
+	ctx, span := tracing.Start(ctx, "weather.GetCurrent", attribute.String("weather.city", q.City))
+	defer func() { tracing.End(span, err) }()
+
+	ctx = logger.With(ctx, "city", q.City)
+
 	return w.pRepo.GetCurrent(ctx, q)
 }
 
-func (w *weatherUseCase) GetForecast(ctx context.Context, q model.ForecastQuery) (*model.AggregatedForecast, error) {
+func (w *weatherUseCase) GetForecast(ctx context.Context, q model.ForecastQuery) (res *model.AggregatedForecast, err error) {
+	ctx, span := tracing.Start(ctx, "weather.GetForecast",
+		attribute.String("weather.city", q.City), attribute.Int("weather.days", q.Days))
+	defer func() { tracing.End(span, err) }()
+
+	ctx = logger.With(ctx, "city", q.City)
+
 	return w.pRepo.GetForecast(ctx, q)
 }
+
+func (w *weatherUseCase) GetHourlyForecast(ctx context.Context, q model.HourlyForecastQuery) (res *model.AggregatedHourlyForecast, err error) {
+	ctx, span := tracing.Start(ctx, "weather.GetHourlyForecast",
+		attribute.String("weather.city", q.City), attribute.Int("weather.hours", q.Hours))
+	defer func() { tracing.End(span, err) }()
+
+	ctx = logger.With(ctx, "city", q.City)
+
+	return w.pRepo.GetHourlyForecast(ctx, q)
+}
+
+func (w *weatherUseCase) GetHistory(ctx context.Context, f model.HistoryFilter) (res *model.WeatherHistory, err error) {
+	ctx, span := tracing.Start(ctx, "weather.GetHistory", attribute.String("weather.city", f.City))
+	defer func() { tracing.End(span, err) }()
+
+	ctx = logger.With(ctx, "city", f.City)
+
+	return w.pRepo.GetHistory(ctx, f)
+}
+
+func (w *weatherUseCase) GetScores(ctx context.Context, f model.ScoresFilter) (res *model.ProviderScores, err error) {
+	ctx, span := tracing.Start(ctx, "weather.GetScores", attribute.String("weather.city", f.City))
+	defer func() { tracing.End(span, err) }()
+
+	ctx = logger.With(ctx, "city", f.City)
+
+	return w.pRepo.GetScores(ctx, f)
+}
+
+func (w *weatherUseCase) GetHealth(ctx context.Context) (res *model.HealthStatus, err error) {
+	ctx, span := tracing.Start(ctx, "weather.GetHealth")
+	defer func() { tracing.End(span, err) }()
+
+	return w.pRepo.GetHealth(ctx)
+}
@@ -0,0 +1,20 @@
+package registry
+
+import (
+	"weather-data-aggregator-service/src/parts/city"
+	"weather-data-aggregator-service/src/parts/city/delivery/http"
+	"weather-data-aggregator-service/src/parts/city/repository/postgres"
+	"weather-data-aggregator-service/src/parts/city/usecase"
+)
+
+func (r *register) NewCityController() city.Controller {
+	return http.NewCityController(r.NewCityUseCase())
+}
+
+func (r *register) NewCityUseCase() city.UseCase {
+	return usecase.NewCityUseCase(r.NewCityPostgresRepository(), r.weatherClient)
+}
+
+func (r *register) NewCityPostgresRepository() city.PostgresRepository {
+	return postgres.NewCityPostgresRepository(r.db)
+}
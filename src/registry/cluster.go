@@ -0,0 +1,15 @@
+package registry
+
+import (
+	"weather-data-aggregator-service/src/parts/cluster"
+	"weather-data-aggregator-service/src/parts/cluster/delivery/http"
+	"weather-data-aggregator-service/src/parts/cluster/usecase"
+)
+
+func (r *register) NewClusterController() cluster.Controller {
+	return http.NewClusterController(r.NewClusterUseCase())
+}
+
+func (r *register) NewClusterUseCase() cluster.UseCase {
+	return usecase.NewClusterUseCase(r.elector)
+}
@@ -0,0 +1,20 @@
+package registry
+
+import (
+	"weather-data-aggregator-service/src/parts/fetchrun"
+	"weather-data-aggregator-service/src/parts/fetchrun/delivery/http"
+	"weather-data-aggregator-service/src/parts/fetchrun/repository/postgres"
+	"weather-data-aggregator-service/src/parts/fetchrun/usecase"
+)
+
+func (r *register) NewFetchRunController() fetchrun.Controller {
+	return http.NewFetchRunController(r.NewFetchRunUseCase())
+}
+
+func (r *register) NewFetchRunUseCase() fetchrun.UseCase {
+	return usecase.NewFetchRunUseCase(r.NewFetchRunPostgresRepository())
+}
+
+func (r *register) NewFetchRunPostgresRepository() fetchrun.PostgresRepository {
+	return postgres.NewFetchRunPostgresRepository(r.db)
+}
@@ -0,0 +1,21 @@
+package registry
+
+import (
+	health_checks "weather-data-aggregator-service/src/infrastructure/health"
+	"weather-data-aggregator-service/src/parts/health"
+	"weather-data-aggregator-service/src/parts/health/delivery/http"
+	"weather-data-aggregator-service/src/parts/health/usecase"
+)
+
+func (r *register) NewHealthController() health.Controller {
+	return http.NewHealthController(r.NewHealthUseCase())
+}
+
+func (r *register) NewHealthUseCase() health.UseCase {
+	return usecase.NewHealthUseCase(
+		health_checks.NewPostgresChecker(r.db),
+		health_checks.NewRedisChecker(r.rdb),
+		health_checks.NewBreakerChecker(r.weatherClient),
+		health_checks.NewFreshnessChecker(r.db, r.weatherClient),
+	)
+}
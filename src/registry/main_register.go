@@ -3,30 +3,48 @@ package registry
 import (
 	"github.com/go-redis/redis/v8"
 	"github.com/uptrace/bun"
+	"weather-data-aggregator-service/src/infrastructure/leader"
 	weather_client "weather-data-aggregator-service/src/infrastructure/weather"
+	"weather-data-aggregator-service/src/parts/city"
+	"weather-data-aggregator-service/src/parts/cluster"
+	"weather-data-aggregator-service/src/parts/fetchrun"
+	"weather-data-aggregator-service/src/parts/health"
+	"weather-data-aggregator-service/src/parts/station"
 	"weather-data-aggregator-service/src/parts/weather"
 )
 
 type APIController struct {
-	Weather interface{ weather.Controller }
+	Weather  interface{ weather.Controller }
+	City     interface{ city.Controller }
+	Cluster  interface{ cluster.Controller }
+	FetchRun interface{ fetchrun.Controller }
+	Health   interface{ health.Controller }
+	Station  interface{ station.Controller }
 }
 
 type register struct {
 	db            *bun.DB
 	rdb           *redis.Client
 	weatherClient *weather_client.WeatherClient
+	elector       *leader.Elector
 }
 
 type Registry interface {
 	NewAPIController() APIController
+	RegisterCacheInvalidation()
 }
 
-func NewRegistry(db *bun.DB, rdb *redis.Client, weatherClient *weather_client.WeatherClient) Registry {
-	return &register{db, rdb, weatherClient}
+func NewRegistry(db *bun.DB, rdb *redis.Client, weatherClient *weather_client.WeatherClient, elector *leader.Elector) Registry {
+	return &register{db, rdb, weatherClient, elector}
 }
 
 func (r *register) NewAPIController() APIController {
 	return APIController{
-		Weather: r.NewWeatherController(),
+		Weather:  r.NewWeatherController(),
+		City:     r.NewCityController(),
+		Cluster:  r.NewClusterController(),
+		FetchRun: r.NewFetchRunController(),
+		Health:   r.NewHealthController(),
+		Station:  r.NewStationController(),
 	}
 }
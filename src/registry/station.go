@@ -0,0 +1,20 @@
+package registry
+
+import (
+	"weather-data-aggregator-service/src/parts/station"
+	"weather-data-aggregator-service/src/parts/station/delivery/http"
+	"weather-data-aggregator-service/src/parts/station/repository/postgres"
+	"weather-data-aggregator-service/src/parts/station/usecase"
+)
+
+func (r *register) NewStationController() station.Controller {
+	return http.NewStationController(r.NewStationUseCase())
+}
+
+func (r *register) NewStationUseCase() station.UseCase {
+	return usecase.NewStationUseCase(r.NewStationPostgresRepository())
+}
+
+func (r *register) NewStationPostgresRepository() station.PostgresRepository {
+	return postgres.NewStationPostgresRepository(r.db)
+}
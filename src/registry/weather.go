@@ -1,9 +1,13 @@
 package registry
 
 import (
+	"context"
+	"weather-data-aggregator-service/src/domain/model"
+	"weather-data-aggregator-service/src/infrastructure/logger"
 	"weather-data-aggregator-service/src/parts/weather"
 	"weather-data-aggregator-service/src/parts/weather/delivery/http"
 	"weather-data-aggregator-service/src/parts/weather/repository/postgres"
+	"weather-data-aggregator-service/src/parts/weather/repository/redis"
 	"weather-data-aggregator-service/src/parts/weather/usecase"
 )
 
@@ -12,7 +16,23 @@ func (r *register) NewWeatherController() weather.Controller {
 }
 
 func (r *register) NewWeatherUseCase() weather.UseCase {
-	return usecase.NewWeatherUseCase(r.NewWeatherPostgresRepository())
+	return usecase.NewWeatherUseCase(r.NewWeatherCacheRepository())
+}
+
+func (r *register) NewWeatherCacheRepository() weather.CacheRepository {
+	return redis.NewWeatherCacheRepository(r.rdb, r.NewWeatherPostgresRepository())
+}
+
+// RegisterCacheInvalidation drops cached current weather when ingestion
+// stores new aggregate, API may run in another process sharing the cache
+func (r *register) RegisterCacheInvalidation() {
+	repo := r.NewWeatherCacheRepository()
+
+	r.weatherClient.OnAggregate(func(ctx context.Context, city *model.City) {
+		if err := repo.Invalidate(ctx, city); err != nil {
+			logger.Ctx(ctx).Error().Err(err).Msg("cache.invalidate_failed")
+		}
+	})
 }
 
 func (r *register) NewWeatherPostgresRepository() weather.PostgresRepository {
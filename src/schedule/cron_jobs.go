@@ -1,18 +1,36 @@
 package scheduled_tasks
 
 import (
-	"github.com/gofiber/fiber/v2/log"
+	"context"
 	crn "github.com/robfig/cron/v3"
+	"github.com/rs/zerolog/log"
 	"weather-data-aggregator-service/src/infrastructure/weather"
 )
 
-func RunCronJobs(weatherClient *weather.WeatherClient) {
-	cronJobRunner := crn.New()
+// cronLogger writes cron events (skipped overlapping runs, panics) to service logger
+type cronLogger struct{}
 
-	err := weather.InitWeatherCronJobs(cronJobRunner, weatherClient)
+func (cronLogger) Info(msg string, keysAndValues ...interface{}) {
+	log.Debug().Fields(keysAndValues).Msg("cron." + msg)
+}
+
+func (cronLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	log.Error().Err(err).Fields(keysAndValues).Msg("cron." + msg)
+}
+
+func RunCronJobs(ctx context.Context, weatherClient *weather.WeatherClient) *crn.Cron {
+	// Slow fetch cycle must not overlap with the next one
+	cronJobRunner := crn.New(
+		crn.WithLogger(cronLogger{}),
+		crn.WithChain(crn.SkipIfStillRunning(cronLogger{})),
+	)
+
+	err := weather.InitWeatherCronJobs(ctx, cronJobRunner, weatherClient)
 	if err != nil {
-		log.Fatalf("Failed to init cron jobs: %s", err)
+		log.Fatal().Err(err).Msg("cron.init_failed")
 	}
 
 	cronJobRunner.Start()
+
+	return cronJobRunner
 }
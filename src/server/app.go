@@ -1,18 +1,24 @@
 package server
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/log"
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
-	"io"
 	"net/http"
 	"os"
 	"os/signal"
+	"syscall"
 	"time"
 	serverHttp "weather-data-aggregator-service/src/infrastructure/delivery/http"
+	"weather-data-aggregator-service/src/infrastructure/leader"
+	"weather-data-aggregator-service/src/infrastructure/logger"
+	"weather-data-aggregator-service/src/infrastructure/metrics"
 	"weather-data-aggregator-service/src/infrastructure/storage/postgres"
 	"weather-data-aggregator-service/src/infrastructure/storage/redis"
+	"weather-data-aggregator-service/src/infrastructure/tracing"
 	"weather-data-aggregator-service/src/infrastructure/weather"
 	"weather-data-aggregator-service/src/registry"
 	scheduled_tasks "weather-data-aggregator-service/src/schedule"
@@ -21,6 +27,7 @@ import (
 type App struct {
 	httpServer *http.Server
 	f          *fiber.App
+	lifecycle  lifecycle
 }
 
 //func NewApp() *App {
@@ -69,7 +76,33 @@ type App struct {
 //	}
 //}
 
-func NewApp() *App {
+// Mode selects which parts of the service the process runs
+type Mode string
+
+const (
+	// ModeAPI serves read-only HTTP API, no scheduled ingestion.
+	// Live lookups use keyless providers only.
+	ModeAPI Mode = "api"
+	// ModeWorker runs scheduler and ingestion, serves admin API
+	// and station pushes
+	ModeWorker Mode = "worker"
+	// ModeAll runs API and worker in single process
+	ModeAll Mode = "all"
+)
+
+func (m Mode) serves() bool {
+	return m == ModeAPI || m == ModeAll
+}
+
+func (m Mode) ingests() bool {
+	return m == ModeWorker || m == ModeAll
+}
+
+func NewApp(mode Mode) *App {
+	if !mode.serves() && !mode.ingests() {
+		panic(fmt.Sprintf("unknown mode %q", mode))
+	}
+
 	// timezone
 	location, err := time.LoadLocation(viper.GetString("server_timezone"))
 	if err != nil {
@@ -78,27 +111,134 @@ func NewApp() *App {
 	time.Local = location
 
 	// logging
-	file, _ := os.OpenFile("test.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-	iw := io.MultiWriter(os.Stdout, file)
-	log.SetOutput(iw)
+	logFile, err := logger.Init()
+	if err != nil {
+		panic(err)
+	}
+
+	log.Info().Str("mode", string(mode)).Msg("server.starting")
+
+	shutdownTracing, err := tracing.Init(context.Background())
+	if err != nil {
+		panic(err)
+	}
 
 	db := postgres.InitPostgres()
 	rdb := redis.InitRedis()
-	weatherClient := weather.InitWeatherAPI(db)
+	// API instances are public-facing, they get no provider keys
+	weatherClient := weather.InitWeatherAPI(db, mode == ModeAPI)
+
+	// Latest stored weather is scraped from any instance, data comes from DB
+	metrics.RegisterWeatherCollector(db)
 
-	apiController := registry.NewRegistry(db, rdb, weatherClient).NewAPIController()
+	// ctx is cancelled when running fetch cycle does not finish in grace period
+	ctx, cancel := context.WithCancel(context.Background())
+
+	elector := leader.NewElector(rdb, db)
+	reg := registry.NewRegistry(db, rdb, weatherClient, elector)
+	apiController := reg.NewAPIController()
 
 	f := fiber.New(fiber.Config{
 		ErrorHandler: fiberErrorHandler,
 	})
 
-	serverHttp.NewFiberRouter(f, apiController)
+	switch mode {
+	case ModeAPI:
+		serverHttp.NewAPIRouter(f, apiController)
+	case ModeWorker:
+		serverHttp.NewWorkerRouter(f, apiController)
+	default:
+		serverHttp.NewFiberRouter(f, apiController)
+	}
 
-	scheduled_tasks.RunCronJobs(weatherClient)
+	// Both API (nearest city) and worker (fetch cycle) need fresh city list
+	go weatherClient.WatchCities(ctx)
 
-	return &App{
+	a := &App{
 		f: f,
 	}
+
+	a.lifecycle.onShutdown("http", f.ShutdownWithContext)
+
+	if mode.ingests() {
+		reg.RegisterCacheInvalidation()
+
+		resign := elector.Resign
+
+		viper.SetDefault("leader.enabled", true)
+		if viper.GetBool("leader.enabled") {
+			weatherClient.SetLeadership(elector)
+
+			leaderCtx, stopLeader := context.WithCancel(ctx)
+			leaderDone := make(chan struct{})
+			go func() {
+				defer close(leaderDone)
+				elector.Run(leaderCtx)
+			}()
+
+			// Running loop would acquire released lease again, with new token
+			resign = func(ctx context.Context) error {
+				stopLeader()
+				select {
+				case <-leaderDone:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+				return elector.Resign(ctx)
+			}
+		}
+
+		cronJobRunner := scheduled_tasks.RunCronJobs(ctx, weatherClient)
+
+		viper.SetDefault("shutdown.flush_reserve", 10*time.Second)
+
+		a.lifecycle.onShutdown("scheduler", func(ctx context.Context) error {
+			stopped := cronJobRunner.Stop()
+
+			// End of grace period is reserved for cancelled cycle to flush
+			// already fetched data, at most half of the remaining time
+			waitCtx := ctx
+			if deadline, ok := ctx.Deadline(); ok {
+				reserve := min(viper.GetDuration("shutdown.flush_reserve"), time.Until(deadline)/2)
+
+				var cancelWait context.CancelFunc
+				waitCtx, cancelWait = context.WithDeadline(ctx, deadline.Add(-reserve))
+				defer cancelWait()
+			}
+
+			select {
+			case <-stopped.Done():
+				return nil
+			case <-waitCtx.Done():
+			}
+
+			cancel()
+			select {
+			case <-stopped.Done():
+			case <-ctx.Done():
+			}
+
+			return fmt.Errorf("fetch cycle cancelled: %w", waitCtx.Err())
+		})
+		a.lifecycle.onShutdown("leader", resign)
+	}
+
+	a.lifecycle.onShutdown("background", func(context.Context) error {
+		cancel()
+		return nil
+	})
+	a.lifecycle.onShutdown("redis", func(context.Context) error {
+		return rdb.Close()
+	})
+	a.lifecycle.onShutdown("postgres", func(context.Context) error {
+		return db.Close()
+	})
+	a.lifecycle.onShutdown("tracing", shutdownTracing)
+	a.lifecycle.onShutdown("logger", func(context.Context) error {
+		return logFile.Close()
+	})
+
+	return a
 }
 
 func (a *App) Run() error {
@@ -109,10 +249,18 @@ func (a *App) Run() error {
 	}()
 
 	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt)
-	<-quit
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	sig := <-quit
+
+	viper.SetDefault("shutdown.grace_period", 30*time.Second)
+	grace := viper.GetDuration("shutdown.grace_period")
+
+	log.Info().Str("signal", sig.String()).Dur("grace_period", grace).Msg("server.shutting_down")
 
-	return a.f.Shutdown()
+	ctx, cancel := context.WithTimeout(context.Background(), grace)
+	defer cancel()
+
+	return a.lifecycle.shutdown(ctx)
 }
 
 //func (a *App) Run() error {
@@ -145,12 +293,15 @@ var fiberErrorHandler = func(c *fiber.Ctx, err error) error {
 		err = errors.New(e.Message)
 	}
 
-	log.Errorf("[ERROR] %s %s - %v", c.Method(), c.OriginalURL(), err)
+	// Every request is in access log, only server errors need details
+	if code >= fiber.StatusInternalServerError {
+		logger.Ctx(c.UserContext()).Error().Err(err).Str("path", tracing.RedactedURL(c)).Msg("http.error")
+	}
 
 	return c.Status(code).JSON(fiber.Map{
 		"error":  err.Error(),
 		"status": code,
-		"path":   c.OriginalURL(),
+		"path":   tracing.RedactedURL(c),
 		"time":   time.Now().Format(time.RFC3339),
 	})
 }
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+// shutdownHook releases single resource, ctx is bounded by grace period
+type shutdownHook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// lifecycle stops registered resources in registration order
+type lifecycle struct {
+	hooks []shutdownHook
+}
+
+func (l *lifecycle) onShutdown(name string, fn func(ctx context.Context) error) {
+	l.hooks = append(l.hooks, shutdownHook{name, fn})
+}
+
+// shutdown runs every hook even if previous ones failed and joins their errors
+func (l *lifecycle) shutdown(ctx context.Context) error {
+	var errs []error
+
+	for _, hook := range l.hooks {
+		start := time.Now()
+
+		if err := hook.fn(ctx); err != nil {
+			log.Error().Err(err).Str("hook", hook.name).Msg("shutdown.hook_failed")
+			errs = append(errs, fmt.Errorf("%s: %w", hook.name, err))
+			continue
+		}
+
+		log.Info().Str("hook", hook.name).Dur("duration", time.Since(start)).Msg("shutdown.hook_done")
+	}
+
+	return errors.Join(errs...)
+}